@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/client/tui"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// browseCommand opens the full-screen browser on the release on disk, it works offline like lookup
+func browseCommand(flags *flag.FlagSet) runFunc {
+	return func(config types.ServerConfig, args []string, std streams) int {
+		if !isTerminal(std.out) {
+			return usageError(std, "browse", "needs a terminal, use 'tango lookup' to pipe results")
+		}
+
+		// Logs would be drawn over the screen, only errors are worth showing and they're returned
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		lookup, err := server.OpenLookup(context.Background(), config)
+		if err != nil {
+			fmt.Fprintf(std.err, "Couldn't open the dictionary: %v\n", err)
+			return exitError
+		}
+		defer lookup.Close(context.Background())
+
+		release := lookup.Release()
+		err = tui.Run(lookup, tui.Options{
+			PageSize: config.Search.MaxSize,
+			Title:    fmt.Sprintf("JMdict %s, release %s", release.JmdictVersion, release.Generation),
+			Query:    strings.Join(args, " "),
+		}, std.in, std.out)
+		if err != nil {
+			fmt.Fprintf(std.err, "Error: %v\n", err)
+			return exitError
+		}
+
+		return exitOK
+	}
+}
@@ -0,0 +1,139 @@
+// Package cli is the 'tango' command. Every subcommand takes the configuration flags of
+// common/config next to its own, and exits with the same codes.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/izquiratops/tango/common/config"
+	"github.com/izquiratops/tango/common/logging"
+	"github.com/izquiratops/tango/common/types"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1 // The command ran and failed
+	exitUsage = 2 // Unknown command, invalid flags or arguments, or invalid configuration
+)
+
+// streams are what a command reads from and writes to
+type streams struct {
+	in  io.Reader
+	out io.Writer
+	err io.Writer
+}
+
+// runFunc runs a command once its flags are parsed, args are the ones left after the flags
+type runFunc func(config types.ServerConfig, args []string, std streams) int
+
+type command struct {
+	name    string
+	args    string // Arguments after the flags, for the usage line
+	summary string
+	// setup adds the flags of the command, and returns what runs it once they're parsed
+	setup func(flags *flag.FlagSet) runFunc
+	// optionalConfig commands run even when the configuration is invalid, with what could be loaded
+	optionalConfig bool
+}
+
+var commands = []command{
+	{name: "serve", summary: "Serve the web dictionary and the JSON API", setup: serveCommand},
+	{name: "import", summary: "Import the dictionary sources into a new release and promote it", setup: importCommand},
+	{name: "lookup", args: "<query>", summary: "Search the current release from the terminal", setup: lookupCommand},
+	{name: "browse", args: "[query]", summary: "Browse the current release in a full-screen terminal UI", setup: browseCommand},
+	{name: "furigana", args: "[text]", summary: "Annotate text with readings, read from stdin when it's not given", setup: furiganaCommand},
+	{name: "verify", summary: "Check the current release: counts and sample lookups against its manifest", setup: verifyCommand},
+	{name: "export", summary: "Write every document of the current release as JSON lines", setup: exportCommand},
+	{name: "version", summary: "Print the version of tango and of the release being served", setup: versionCommand, optionalConfig: true},
+}
+
+// Run runs the subcommand named by the first argument, and returns the exit code
+func Run(args []string) int {
+	return run(args, streams{in: os.Stdin, out: os.Stdout, err: os.Stderr})
+}
+
+func run(args []string, std streams) int {
+	if len(args) == 0 {
+		printUsage(std.err)
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		if len(args) > 1 {
+			// 'tango help serve' is 'tango serve -help'
+			return run([]string{args[1], "-help"}, std)
+		}
+		printUsage(std.out)
+		return exitOK
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.execute(args[1:], std)
+		}
+	}
+
+	fmt.Fprintf(std.err, "tango: unknown command %q\nRun 'tango help' for the list of commands.\n", name)
+	return exitUsage
+}
+
+func (c command) execute(args []string, std streams) int {
+	flags := flag.NewFlagSet("tango "+c.name, flag.ContinueOnError)
+	flags.SetOutput(std.err)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: tango %s [flags] %s\n%s.\n\nFlags:\n", c.name, c.args, c.summary)
+		flags.PrintDefaults()
+	}
+
+	runCommand := c.setup(flags)
+	loader := config.NewLoader(flags)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	config, printed, err := loader.LoadOrPrint(std.out)
+	if err != nil && (printed || !c.optionalConfig) {
+		fmt.Fprintf(std.err, "Invalid config: %v\n", err)
+		return exitUsage
+	}
+	if printed {
+		return exitOK
+	}
+
+	slog.SetDefault(logging.New(std.err, config.LogFormat, config.LogLevel))
+	return runCommand(config, flags.Args(), std)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Tango is a Japanese-English dictionary.\n\nUsage: tango <command> [flags] [arguments]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nEvery command takes the configuration flags, run 'tango help <command>' to list them.\n")
+	fmt.Fprintf(w, "Exit codes: %d on success, %d when the command fails, %d for invalid usage or configuration.\n", exitOK, exitError, exitUsage)
+}
+
+// usageError reports invalid arguments the way invalid flags are reported
+func usageError(std streams, command string, format string, args ...any) int {
+	fmt.Fprintf(std.err, "tango %s: %s\nRun 'tango help %s' for usage.\n", command, fmt.Sprintf(format, args...), command)
+	return exitUsage
+}
+
+// withNewline ends the text with a line break, unless it already does
+func withNewline(text string) string {
+	if strings.HasSuffix(text, "\n") {
+		return text
+	}
+
+	return text + "\n"
+}
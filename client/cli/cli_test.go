@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunExitCodes(t *testing.T) {
+	// Runs without a JMdict version unless a test gives one
+	t.Setenv("TANGO_VERSION", "")
+	t.Setenv("TANGO_CONFIG", "")
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"no command", nil, exitUsage, "", "Usage: tango <command>"},
+		{"unknown command", []string{"fetch"}, exitUsage, "", `unknown command "fetch"`},
+		{"help", []string{"help"}, exitOK, "lookup", ""},
+		{"help of a command", []string{"help", "export"}, exitOK, "", "-kind"},
+		{"command help flag", []string{"lookup", "-h"}, exitOK, "", "Usage: tango lookup"},
+		{"invalid flag", []string{"serve", "-nope"}, exitUsage, "", "-nope"},
+		{"invalid config", []string{"verify"}, exitUsage, "", "Invalid config: version"},
+		{"invalid config value", []string{"lookup", "-version", "3.6.1", "-search-max-size", "x", "eat"}, exitUsage, "", "search.max_size (from flag -search-max-size)"},
+		{"invalid argument", []string{"lookup", "-version", "3.6.1", "-format", "xml", "eat"}, exitUsage, "", `unknown format "xml"`},
+		{"unexpected argument", []string{"export", "-version", "3.6.1", "words"}, exitUsage, "", `unexpected argument "words"`},
+		{"print config", []string{"serve", "-version", "3.6.1", "-print-config"}, exitOK, "version: 3.6.1", ""},
+		{"version without config", []string{"version"}, exitOK, "tango ", ""},
+		{"version without release", []string{"version", "-version", "3.6.1", "-data-dir", "missing"}, exitOK, "JMdict 3.6.1: no release", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, streams{in: strings.NewReader(""), out: &stdout, err: &stderr})
+
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d (stderr %q)", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
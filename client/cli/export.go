@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+)
+
+const exportBatchSize = 1000
+
+var exportKinds = []string{"words", "kanji", "names", "sentences", "radicals", "tags"}
+
+// exportCommand writes one document per line, in the same JSON the API answers with
+func exportCommand(flags *flag.FlagSet) runFunc {
+	kindFlag := flags.String("kind", "words", "Documents to export: "+strings.Join(exportKinds, ", "))
+
+	return func(config types.ServerConfig, args []string, std streams) int {
+		if len(args) > 0 {
+			return usageError(std, "export", "unexpected argument %q", args[0])
+		}
+
+		release, err := database.CurrentRelease(config.DataDir, config.JmdictVersion)
+		if err != nil {
+			fmt.Fprintf(std.err, "Couldn't find the current release: %v\n", err)
+			return exitError
+		}
+
+		ctx := context.Background()
+		db, err := database.OpenDatabase(ctx, &config, release)
+		if err != nil {
+			fmt.Fprintf(std.err, "Couldn't open the dictionary: %v\n", err)
+			return exitError
+		}
+		defer db.Close(ctx)
+
+		out := bufio.NewWriter(std.out)
+		encoder := json.NewEncoder(out)
+
+		var count int
+		switch *kindFlag {
+		case "words":
+			count, err = exportIndex(ctx, db.BleveIndex, db.Store.GetWords, encoder)
+		case "kanji":
+			count, err = exportIndex(ctx, db.KanjiIndex, db.Store.GetKanji, encoder)
+		case "names":
+			count, err = exportIndex(ctx, db.NamesIndex, db.Store.GetNames, encoder)
+		case "sentences":
+			count, err = exportIndex(ctx, db.SentencesIndex, db.Store.GetSentences, encoder)
+		case "radicals":
+			count, err = exportAll(ctx, db.Store.GetRadicals, encoder)
+		case "tags":
+			count, err = exportAll(ctx, db.Store.GetTags, encoder)
+		default:
+			return usageError(std, "export", "unknown kind %q, must be one of %s", *kindFlag, strings.Join(exportKinds, ", "))
+		}
+		if err == nil {
+			err = out.Flush()
+		}
+		if err != nil {
+			fmt.Fprintf(std.err, "Error exporting %s: %v\n", *kindFlag, err)
+			return exitError
+		}
+
+		slog.Info("Export finished", "kind", *kindFlag, "documents", count, "generation", release.Generation)
+		return exitOK
+	}
+}
+
+// exportIndex walks every ID of the index in order and writes the documents the store has for
+// them. Pages continue after the last ID seen, so deep pages cost the same as the first one.
+func exportIndex[T any](ctx context.Context, index bleve.Index, get func(context.Context, []string) ([]T, error), encoder *json.Encoder) (int, error) {
+	var count int
+	var after []string
+
+	for {
+		searchRequest := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), exportBatchSize, 0, false)
+		searchRequest.SortBy([]string{"_id"})
+		searchRequest.SearchAfter = after
+
+		searchResults, err := index.Search(searchRequest)
+		if err != nil {
+			return count, fmt.Errorf("error listing Bleve documents: %v", err)
+		}
+		if len(searchResults.Hits) == 0 {
+			return count, nil
+		}
+
+		ids := make([]string, len(searchResults.Hits))
+		for i, hit := range searchResults.Hits {
+			ids[i] = hit.ID
+		}
+
+		documents, err := get(ctx, ids)
+		if err != nil {
+			return count, err
+		}
+		for _, document := range documents {
+			if err := encoder.Encode(document); err != nil {
+				return count, err
+			}
+		}
+
+		count += len(documents)
+		after = []string{ids[len(ids)-1]}
+	}
+}
+
+// exportAll writes documents that aren't indexed, the store returns all of them at once
+func exportAll[T any](ctx context.Context, get func(context.Context) ([]T, error), encoder *json.Encoder) (int, error) {
+	documents, err := get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(documents), nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// furiganaCommand annotates the text given as arguments, or read from stdin, and prints it.
+// It opens the current release read-only, the same way lookup does.
+func furiganaCommand(flags *flag.FlagSet) runFunc {
+	formatFlag := flags.String("format", "html", "Output format: html, anki or json")
+	skipCommonFlag := flags.Bool("skip-common", false, "Leave common words without reading")
+	skipJLPTFlag := flags.Int("skip-jlpt", 0, "Leave words whose kanji are all at this JLPT level or easier without reading (old 4 to 1 scale)")
+
+	return func(config types.ServerConfig, args []string, std streams) int {
+		format, err := furigana.ParseFormat(*formatFlag)
+		if err != nil {
+			return usageError(std, "furigana", "%v", err)
+		}
+
+		text := strings.Join(args, " ")
+		if text == "" {
+			input, err := io.ReadAll(std.in)
+			if err != nil {
+				fmt.Fprintf(std.err, "Error reading stdin: %v\n", err)
+				return exitError
+			}
+			text = string(input)
+		}
+
+		// Setup messages are logged to stderr, so stdout only has the annotated text
+		lookup, err := server.OpenLookup(context.Background(), config)
+		if err != nil {
+			fmt.Fprintf(std.err, "Couldn't open the dictionary: %v\n", err)
+			return exitError
+		}
+		defer lookup.Close(context.Background())
+
+		spans, err := lookup.Annotate(context.Background(), text, server.FuriganaOptions{
+			SkipCommon: *skipCommonFlag,
+			SkipJLPT:   *skipJLPTFlag,
+		})
+		if err != nil {
+			fmt.Fprintf(std.err, "Error annotating text: %v\n", err)
+			return exitError
+		}
+
+		switch format {
+		case furigana.HTMLFormat:
+			fmt.Fprint(std.out, withNewline(furigana.HTML(spans)))
+		case furigana.AnkiFormat:
+			fmt.Fprint(std.out, withNewline(furigana.Anki(spans)))
+		case furigana.JSONFormat:
+			encoder := json.NewEncoder(std.out)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(spans); err != nil {
+				fmt.Fprintf(std.err, "Error encoding JSON: %v\n", err)
+				return exitError
+			}
+		}
+
+		return exitOK
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/izquiratops/tango/common/types"
+	"github.com/izquiratops/tango/import/importer"
+)
+
+func importCommand(flags *flag.FlagSet) runFunc {
+	options := importer.RegisterFlags(flags)
+
+	return func(config types.ServerConfig, args []string, std streams) int {
+		if len(args) > 0 {
+			return usageError(std, "import", "unexpected argument %q, sources are given with flags", args[0])
+		}
+
+		if err := importer.Run(&config, *options); err != nil {
+			fmt.Fprintf(std.err, "Error Details: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+)
+
+const (
+	textFormat = "text"
+	jsonFormat = "json"
+	tsvFormat  = "tsv"
+)
+
+// lookupCommand searches words the same way the web page does, from the release on disk and
+// without a server running. Without a query, every line of stdin is a query.
+func lookupCommand(flags *flag.FlagSet) runFunc {
+	pageFlag := flags.Int("page", 1, "Page of results to print")
+	sizeFlag := flags.Int("size", 0, "Results per page, defaults to search.default_size")
+	formatFlag := flags.String("format", textFormat, "Output format: text, json (one search response per line, as /api/v1/search) or tsv (one word per line)")
+	colorFlag := flags.String("color", "auto", "Color the text output: auto (when writing to a terminal and NO_COLOR isn't set), always or never")
+
+	return func(config types.ServerConfig, args []string, std streams) int {
+		options := server.SearchOptions{Page: *pageFlag, Size: config.Search.DefaultSize}
+		if *sizeFlag != 0 {
+			options.Size = *sizeFlag
+		}
+		if options.Page < 1 || options.Size < 1 || options.Size > config.Search.MaxSize {
+			return usageError(std, "lookup", "page must be positive and size between 1 and %d", config.Search.MaxSize)
+		}
+
+		var out lookupPrinter
+		switch *formatFlag {
+		case textFormat:
+			colors, err := parseColor(*colorFlag, std.out)
+			if err != nil {
+				return usageError(std, "lookup", "%v", err)
+			}
+			out = &textPrinter{colors: colors}
+		case jsonFormat:
+			out = &jsonPrinter{}
+		case tsvFormat:
+			out = &tsvPrinter{}
+		default:
+			return usageError(std, "lookup", "unknown format %q, must be text, json or tsv", *formatFlag)
+		}
+
+		// A single query comes from the arguments, a batch from stdin
+		query := strings.TrimSpace(strings.Join(args, " "))
+		batch := query == ""
+
+		lookup, err := server.OpenLookup(context.Background(), config)
+		if err != nil {
+			fmt.Fprintf(std.err, "Couldn't open the dictionary: %v\n", err)
+			return exitError
+		}
+		defer lookup.Close(context.Background())
+
+		w := bufio.NewWriter(std.out)
+		defer w.Flush()
+
+		exitCode := exitOK
+		search := func(query string) error {
+			result, err := lookup.Search(context.Background(), query, options)
+			if err != nil && !errors.Is(err, server.ErrNoResults) {
+				fmt.Fprintf(std.err, "Error searching %q: %v\n", query, err)
+				exitCode = exitError
+				return nil
+			}
+			if len(result.Words) == 0 {
+				exitCode = exitError
+			}
+
+			if err := out.print(w, lookup, result, batch); err != nil {
+				return err
+			}
+			// Batches are read line by line, answer each one as soon as it's found
+			return w.Flush()
+		}
+
+		if !batch {
+			err = search(query)
+		} else {
+			err = eachLine(std.in, search)
+		}
+		if err != nil {
+			fmt.Fprintf(std.err, "Error: %v\n", err)
+			return exitError
+		}
+
+		return exitCode
+	}
+}
+
+// eachLine calls fn with every line of the input that isn't blank
+func eachLine(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading stdin: %w", err)
+	}
+	return nil
+}
+
+type lookupPrinter interface {
+	// print writes the result of one query, batch tells if more queries are printed around it
+	print(w io.Writer, lookup *server.Lookup, result *server.SearchResult, batch bool) error
+}
+
+// palette holds the ANSI escapes of the text output, they're all empty without colors
+type palette struct {
+	bold, dim, reading, common, tag, reset string
+}
+
+func parseColor(mode string, out io.Writer) (palette, error) {
+	colors := palette{bold: "\033[1m", dim: "\033[2m", reading: "\033[36m", common: "\033[32m", tag: "\033[33m", reset: "\033[0m"}
+
+	switch mode {
+	case "always":
+		return colors, nil
+	case "never":
+		return palette{}, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(out) {
+			return colors, nil
+		}
+		return palette{}, nil
+	default:
+		return palette{}, fmt.Errorf("unknown color mode %q, must be auto, always or never", mode)
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// textPrinter writes the results for people, a heading per word followed by its senses
+type textPrinter struct {
+	colors  palette
+	printed bool
+}
+
+func (p *textPrinter) print(w io.Writer, lookup *server.Lookup, result *server.SearchResult, batch bool) error {
+	c := p.colors
+
+	if batch {
+		if p.printed {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s» %s%s\n", c.bold, result.Query, c.reset)
+	}
+	p.printed = true
+
+	for _, inflection := range result.Inflections {
+		fmt.Fprintf(w, "%s%s is the %s of %s%s\n", c.dim, result.Query, inflection.Reason, inflection.Base, c.reset)
+	}
+
+	for _, word := range result.Words {
+		fmt.Fprintf(w, "%s%s%s", c.bold, word.MainWord.Word, c.reset)
+		if word.MainWord.Reading != "" {
+			fmt.Fprintf(w, " %s【%s】%s", c.reading, word.MainWord.Reading, c.reset)
+		}
+		if word.Common {
+			fmt.Fprintf(w, " %scommon%s", c.common, c.reset)
+		}
+		fmt.Fprintln(w)
+
+		if forms := otherForms(word); forms != "" {
+			fmt.Fprintf(w, "  %salso %s%s\n", c.dim, forms, c.reset)
+		}
+
+		for i, meaning := range word.Meanings {
+			fmt.Fprintf(w, "  %s%d.%s %s", c.dim, i+1, c.reset, meaning)
+			if i < len(word.Senses) && len(word.Senses[i].PartOfSpeech) > 0 {
+				fmt.Fprintf(w, " %s[%s]%s", c.tag, strings.Join(word.Senses[i].PartOfSpeech, ", "), c.reset)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if len(result.Words) == 0 {
+		_, err := fmt.Fprintf(w, "%sNo results%s\n", c.dim, c.reset)
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s%d-%d of %d results%s\n", c.dim, result.From+1, result.From+len(result.Words), result.Total, c.reset)
+	return err
+}
+
+func otherForms(word database.Word) string {
+	forms := make([]string, 0, len(word.OtherForms))
+	for _, form := range word.OtherForms {
+		if form.Reading != "" {
+			forms = append(forms, form.Word+"【"+form.Reading+"】")
+		} else {
+			forms = append(forms, form.Word)
+		}
+	}
+
+	return strings.Join(forms, "、")
+}
+
+// jsonPrinter writes a line per query, with the same response as /api/v1/search
+type jsonPrinter struct{}
+
+func (p *jsonPrinter) print(w io.Writer, lookup *server.Lookup, result *server.SearchResult, batch bool) error {
+	return json.NewEncoder(w).Encode(lookup.APIResponse(result))
+}
+
+// tsvPrinter writes a line per word, after a header line
+type tsvPrinter struct {
+	printedHeader bool
+}
+
+var tsvEscaper = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+func (p *tsvPrinter) print(w io.Writer, lookup *server.Lookup, result *server.SearchResult, batch bool) error {
+	if !p.printedHeader {
+		p.printedHeader = true
+		if _, err := fmt.Fprintln(w, "query\tid\tword\treading\tcommon\tmeanings"); err != nil {
+			return err
+		}
+	}
+
+	for _, word := range result.Words {
+		fields := []string{
+			result.Query,
+			word.ID,
+			word.MainWord.Word,
+			word.MainWord.Reading,
+			fmt.Sprint(word.Common),
+			strings.Join(word.Meanings, " / "),
+		}
+		for i, field := range fields {
+			fields[i] = tsvEscaper.Replace(field)
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
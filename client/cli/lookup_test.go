@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/izquiratops/tango/common/config"
+	"github.com/izquiratops/tango/common/database"
+)
+
+// testRelease imports a couple of words into a bolt release and promotes it
+func testRelease(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+
+	dataDir := t.TempDir()
+	cfg := config.Defaults()
+	cfg.StorageBackend = string(database.BoltBackend)
+
+	release := database.NewRelease(dataDir, "3.6.1")
+	db, err := database.NewDatabase(ctx, &cfg, release)
+	if err != nil {
+		t.Fatalf("Error creating release: %v", err)
+	}
+
+	words := []database.Word{
+		{ID: "1", MainWord: database.Furigana{Word: "食べる", Reading: "たべる"}, Common: true, Meanings: []string{"to eat"},
+			Senses: []database.Sense{{PartOfSpeech: []string{"v1"}, Glosses: []string{"to eat"}}}},
+		{ID: "2", MainWord: database.Furigana{Word: "パン"}, Meanings: []string{"bread"}},
+	}
+	if err := db.Store.PutWords(ctx, words); err != nil {
+		t.Fatalf("Error writing words: %v", err)
+	}
+	if err := db.Store.PutTags(ctx, []database.Tag{{Name: "v1", Description: "Ichidan verb"}}); err != nil {
+		t.Fatalf("Error writing tags: %v", err)
+	}
+	for _, doc := range []database.WordSearchable{
+		{ID: "1", KanjiExact: []string{"食べる"}, KanaExact: []string{"たべる"}, Meanings: []string{"to eat"}, Common: true},
+		{ID: "2", KanaExact: []string{"パン"}, Meanings: []string{"bread"}},
+	} {
+		if err := db.BleveIndex.Index(doc.ID, doc); err != nil {
+			t.Fatalf("Error indexing word: %v", err)
+		}
+	}
+
+	if err := db.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.PromoteRelease(release); err != nil {
+		t.Fatal(err)
+	}
+
+	return dataDir
+}
+
+func TestLookup(t *testing.T) {
+	t.Setenv("TANGO_CONFIG", "")
+	dataDir := testRelease(t)
+	flags := []string{"lookup", "-version", "3.6.1", "-storage", "bolt", "-data-dir", dataDir, "-log-level", "error"}
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		code  int
+		want  string
+	}{
+		{"text", []string{"eat"}, "", exitOK, "食べる 【たべる】 common\n  1. to eat [v1]\n1-1 of 1 results\n"},
+		{"no results", []string{"zzzz"}, "", exitError, "No results\n"},
+		{"batch", []string{"-format", "tsv"}, "eat\n\nbread\n", exitOK,
+			"query\tid\tword\treading\tcommon\tmeanings\neat\t1\t食べる\tたべる\ttrue\tto eat\nbread\t2\tパン\t\tfalse\tbread\n"},
+		{"json", []string{"-format", "json", "bread"}, "", exitOK, `"query":"bread","type":"romaji","total":1`},
+		{"colors", []string{"-color", "always", "bread"}, "", exitOK, "\033[1mパン\033[0m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(append(flags, tt.args...), streams{in: strings.NewReader(tt.stdin), out: &stdout, err: &stderr})
+
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d (stderr %q)", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFurigana(t *testing.T) {
+	t.Setenv("TANGO_CONFIG", "")
+	dataDir := testRelease(t)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"furigana", "-version", "3.6.1", "-storage", "bolt", "-data-dir", dataDir, "-log-level", "error", "-format", "anki", "パンを食べる"}
+	if code := run(args, streams{in: strings.NewReader(""), out: &stdout, err: &stderr}); code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, exitOK, stderr.String())
+	}
+
+	if want := "パンを 食[た]べる\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/common/types"
+)
+
+func serveCommand(flags *flag.FlagSet) runFunc {
+	return func(config types.ServerConfig, args []string, std streams) int {
+		if len(args) > 0 {
+			return usageError(std, "serve", "unexpected argument %q", args[0])
+		}
+		return serve(config)
+	}
+}
+
+// serve runs the server until it gets SIGINT or SIGTERM, then lets in-flight requests finish
+// and closes the dictionary
+func serve(config types.ServerConfig) int {
+	// Docker and watchtower stop the container with SIGTERM, Ctrl+C sends SIGINT
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("Initializing server")
+
+	// Stopping while MongoDB is still starting gives up on it right away
+	server, err := server.NewServer(ctx, config)
+	if err != nil {
+		slog.Error("Couldn't initialize the server", "error", err)
+		return exitError
+	}
+
+	httpServer := &http.Server{
+		Addr:         config.Listen,
+		Handler:      server.SetupRoutes(),
+		ReadTimeout:  config.HTTP.ReadTimeout,
+		WriteTimeout: config.HTTP.WriteTimeout,
+		IdleTimeout:  config.HTTP.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server listening", "address", httpServer.Addr)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	exitCode := exitOK
+	select {
+	case err := <-serveErr:
+		slog.Error("Server failed", "error", err)
+		exitCode = exitError
+	case <-ctx.Done():
+		// A second signal kills the process right away
+		stop()
+		slog.Info("Shutting down, draining in-flight requests", "timeout", config.HTTP.ShutdownTimeout)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.HTTP.ShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Failed to drain requests", "error", err)
+			exitCode = exitError
+		}
+	}
+
+	// The indexes are only closed once no request can use them
+	closeCtx, cancel := context.WithTimeout(context.Background(), config.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := server.Close(closeCtx); err != nil {
+		slog.Error("Failed to close the dictionary", "error", err)
+		exitCode = exitError
+	}
+
+	slog.Info("Server stopped")
+	return exitCode
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+	"github.com/izquiratops/tango/import/importer"
+)
+
+// verifyCommand checks the release being served, e.g. after restoring a backup of the data folder
+func verifyCommand(flags *flag.FlagSet) runFunc {
+	return func(config types.ServerConfig, args []string, std streams) int {
+		if len(args) > 0 {
+			return usageError(std, "verify", "unexpected argument %q", args[0])
+		}
+
+		release, err := database.CurrentRelease(config.DataDir, config.JmdictVersion)
+		if err != nil {
+			fmt.Fprintf(std.err, "Couldn't find the current release: %v\n", err)
+			return exitError
+		}
+
+		if err := importer.Verify(&config, release); err != nil {
+			fmt.Fprintf(std.err, "Release %v is broken: %v\n", release.Generation, err)
+			return exitError
+		}
+
+		fmt.Fprintf(std.out, "Release %v of JMdict %v is valid\n", release.Generation, release.JmdictVersion)
+		return exitOK
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// versionCommand prints the build, and the release that 'tango serve' would serve when the
+// configuration names a JMdict version
+func versionCommand(flags *flag.FlagSet) runFunc {
+	return func(config types.ServerConfig, args []string, std streams) int {
+		if len(args) > 0 {
+			return usageError(std, "version", "unexpected argument %q", args[0])
+		}
+
+		fmt.Fprintf(std.out, "tango %s\n", buildVersion())
+
+		if config.JmdictVersion == "" {
+			return exitOK
+		}
+
+		release, err := database.CurrentRelease(config.DataDir, config.JmdictVersion)
+		if err != nil {
+			fmt.Fprintf(std.out, "JMdict %s: %v\n", config.JmdictVersion, err)
+			return exitOK
+		}
+
+		manifest, err := release.ReadManifest()
+		if err != nil {
+			fmt.Fprintf(std.out, "JMdict %s, release %s (no manifest: %v)\n", release.JmdictVersion, release.Generation, err)
+			return exitOK
+		}
+
+		fmt.Fprintf(std.out, "JMdict %s, release %s, dictionary date %s\n", release.JmdictVersion, release.Generation, manifest.DictDate)
+		return exitOK
+	}
+}
+
+// buildVersion reads the module version, or the commit for builds from a checkout
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+
+	version := info.Main.Version
+	var revision, modified string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				modified = "-dirty"
+			}
+		}
+	}
+	if revision != "" && (version == "" || version == "(devel)") {
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		version = revision + modified
+	}
+	if version == "" {
+		version = "(devel)"
+	}
+
+	return fmt.Sprintf("%s (%s)", version, info.GoVersion)
+}
@@ -0,0 +1,12 @@
+// Command tango serves, imports and searches the dictionary, run 'tango help' for the commands
+package main
+
+import (
+	"os"
+
+	"github.com/izquiratops/tango/client/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:]))
+}
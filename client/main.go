@@ -1,39 +1,33 @@
 package main
 
 import (
-	"fmt"
-	"net/http"
+	"flag"
+	"io"
 	"os"
 
-	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/client/cli"
 	"github.com/izquiratops/tango/common/config"
 )
 
-var mongoDomainMap = map[config.EnvironmentType]string{
-	config.LocalEnv:  "localhost",
-	config.ServerEnv: "mongo", // Client runs in a container, so it can connect to the 'mongo' container
-}
-
+// Same as 'tango serve', or 'tango furigana' when called as 'client [config flags] furigana
+// [flags] [text]'. Kept for the existing scripts, Dockerfile and docs.
 func main() {
-	config, err := config.LoadEnvironment(mongoDomainMap)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
-		os.Exit(1)
-	}
-
-	fmt.Printf("\nInitializing server...\n")
+	os.Exit(cli.Run(legacyArgs(os.Args[1:])))
+}
 
-	server, err := server.NewServer(config)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Couldn't initialize the server: %v\n", err)
-		os.Exit(1)
+// legacyArgs turns the arguments of the old binary into the ones of the tango command. The
+// config flags are parsed first, so only the first argument after them can name the command.
+func legacyArgs(args []string) []string {
+	flags := flag.NewFlagSet("client", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	config.NewLoader(flags)
+
+	// Invalid flags are reported by 'tango serve', which takes the same ones
+	if err := flags.Parse(args); err == nil && flags.Arg(0) == "furigana" {
+		// The furigana command takes the config flags too, so they can go after its name
+		configFlags := args[:len(args)-flags.NArg()]
+		return append(append([]string{"furigana"}, configFlags...), flags.Args()[1:]...)
 	}
 
-	mux := server.SetupRoutes()
-
-	fmt.Printf("Server listening at 0.0.0.0:8080\n")
-	if err := http.ListenAndServe("0.0.0.0:8080", mux); err != nil {
-		fmt.Fprintf(os.Stderr, "Error Details: %v\n", err)
-		os.Exit(1)
-	}
+	return append([]string{"serve"}, args...)
 }
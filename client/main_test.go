@@ -0,0 +1,26 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLegacyArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"serve"}},
+		{[]string{"-listen", "127.0.0.1:9000"}, []string{"serve", "-listen", "127.0.0.1:9000"}},
+		{[]string{"-data-dir", "furigana"}, []string{"serve", "-data-dir", "furigana"}},
+		{[]string{"furigana", "-format", "anki", "学校"}, []string{"furigana", "-format", "anki", "学校"}},
+		{[]string{"-data-dir", "data", "furigana", "furigana"}, []string{"furigana", "-data-dir", "data", "furigana"}},
+		{[]string{"-nope", "furigana"}, []string{"serve", "-nope", "furigana"}},
+	}
+
+	for _, tt := range tests {
+		if got := legacyArgs(tt.args); !slices.Equal(got, tt.want) {
+			t.Errorf("legacyArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/segment"
+)
+
+const analyzeWordsSize = 10 // Words written the same way, the best of them is picked for the token
+
+// AnalyzedToken is a piece of the analyzed text, along with the word it was matched to
+type AnalyzedToken struct {
+	Surface  string          `json:"surface"`            // As written in the text
+	Base     string          `json:"base"`               // Dictionary form, the same as surface unless it's conjugated
+	Reading  string          `json:"reading"`            // Reading of the surface, empty when it's already kana
+	Segments []furigana.Span `json:"segments,omitempty"` // Reading of every kanji of the surface, empty when it couldn't be aligned
+	Reason   string          `json:"reason,omitempty"`   // Applied inflections (e.g. "polite past")
+	Word     *database.Word  `json:"word"`               // Null for punctuation and text that isn't in the dictionary
+}
+
+// Furigana returns the surface along with its reading, to be written as ruby
+func (t AnalyzedToken) Furigana() database.Furigana {
+	return database.Furigana{Word: t.Surface, Reading: t.Reading, Segments: t.Segments}
+}
+
+type Analysis struct {
+	Text   string
+	Tokens []AnalyzedToken
+}
+
+// loadForms reads every kanji and kana form in the words index, sentences are split with them
+func loadForms(index bleve.Index) (segment.Forms, error) {
+	forms := make(segment.Forms)
+
+	for _, field := range []string{"kanji_exact", "kana_exact"} {
+		dict, err := index.FieldDict(field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %v terms: %w", field, err)
+		}
+
+		for {
+			entry, err := dict.Next()
+			if err != nil {
+				dict.Close()
+				return nil, fmt.Errorf("failed to read %v terms: %w", field, err)
+			}
+			if entry == nil {
+				break
+			}
+
+			forms.Add(entry.Term)
+		}
+
+		if err := dict.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return forms, nil
+}
+
+// analyze splits a Japanese text into words and looks each one of them up
+func (s *Server) analyze(ctx context.Context, text string) (*Analysis, error) {
+	dict := s.dictionaryFor(ctx)
+	return analyzeText(ctx, text, dict.db, dict.forms)
+}
+
+// analyzeText splits the text with the forms of a release, and looks the words up in the same release
+func analyzeText(ctx context.Context, text string, db *database.Database, forms segment.Forms) (*Analysis, error) {
+	analysis := &Analysis{
+		Text: text,
+	}
+
+	// The same word is usually found more than once in a text
+	words := make(map[string][]database.Word)
+
+	for _, token := range segment.Segment(text, forms) {
+		analyzed := AnalyzedToken{
+			Surface: token.Surface,
+			Base:    token.Base,
+			Reason:  token.Reason,
+		}
+
+		if token.Known {
+			candidates, ok := words[token.Base]
+			if !ok {
+				ids, err := performExactQuery(ctx, []string{token.Base}, analyzeWordsSize, db)
+				if err != nil {
+					slog.ErrorContext(ctx, "Failed to run Bleve query", "error", err)
+					return nil, err
+				}
+
+				candidates, err = fetchWordsByIDs(ctx, ids, db)
+				if err != nil {
+					slog.ErrorContext(ctx, "Failed to fetch words from store", "error", err)
+					return nil, err
+				}
+				words[token.Base] = candidates
+			}
+
+			if word := pickWord(token, candidates); word != nil {
+				analyzed.Word = word
+				analyzed.Reading = tokenReading(token, *word)
+				analyzed.Segments = tokenSegments(token, *word, analyzed.Reading)
+			}
+		}
+
+		analysis.Tokens = append(analysis.Tokens, analyzed)
+	}
+
+	return analysis, nil
+}
+
+// pickWord chooses the word a token is, common words first. Conjugated tokens
+// also need a word whose part of speech allows the conjugation.
+func pickWord(token segment.Token, candidates []database.Word) *database.Word {
+	var picked *database.Word
+
+	for i, word := range candidates {
+		if !hasForm(word, token.Base) {
+			continue
+		}
+		if token.Conjugated() && !hasPartOfSpeech(word, token.Types) {
+			continue
+		}
+
+		if picked == nil || (word.Common && !picked.Common) {
+			picked = &candidates[i]
+		}
+	}
+
+	return picked
+}
+
+// tokenReading returns how the token is read, using the reading of the word form it was matched to
+func tokenReading(token segment.Token, word database.Word) string {
+	if segment.IsKana(token.Surface) {
+		return ""
+	}
+
+	for _, furigana := range append([]database.Furigana{word.MainWord}, word.OtherForms...) {
+		if furigana.Word != token.Base || furigana.Reading == "" {
+			continue
+		}
+
+		if !token.Conjugated() {
+			return furigana.Reading
+		}
+		return segment.InflectReading(token.Surface, token.Base, furigana.Reading)
+	}
+
+	return ""
+}
+
+// tokenSegments splits the reading of the token over its kanji. Unconjugated tokens use the alignment
+// stored with the word form, conjugated ones are aligned around their okurigana.
+func tokenSegments(token segment.Token, word database.Word, reading string) []furigana.Span {
+	if reading == "" {
+		return nil
+	}
+
+	if !token.Conjugated() {
+		for _, form := range append([]database.Furigana{word.MainWord}, word.OtherForms...) {
+			if form.Word == token.Base && form.Reading == reading && form.Segments != nil {
+				return form.Segments
+			}
+		}
+	}
+
+	if spans := furigana.Align(token.Surface, reading, nil); len(spans) > 1 {
+		return spans
+	}
+	return nil
+}
+
+// sentenceParticles tie the words of a phrase together, a query with one between two words is a sentence
+var sentenceParticles = map[string]bool{
+	"は": true, "が": true, "を": true, "に": true, "で": true, "へ": true,
+	"と": true, "も": true, "の": true, "から": true, "まで": true, "より": true,
+}
+
+// sentencePunctuation only ends or splits sentences, it's never part of a word
+const sentencePunctuation = "。、！？!?,.「」『』"
+
+// looksLikeSentence tells if a query of the search box is better split into words than searched as a whole:
+// it's Japanese, made of several dictionary words, and either reads as a sentence (punctuation, or particles
+// between the words) or isn't a word on its own. Compounds, set phrases and the beginning of a word stay searches.
+func looksLikeSentence(ctx context.Context, dict *dictionary, query string) (bool, error) {
+	query = strings.TrimSpace(query)
+	if DetectSearchTermType(query) == Romaji {
+		return false, nil
+	}
+
+	tokens := segment.Segment(query, dict.forms)
+	known := 0
+	for _, token := range tokens {
+		if token.Known {
+			known++
+		}
+	}
+	if known < 2 {
+		return false, nil
+	}
+
+	if strings.ContainsAny(query, sentencePunctuation) {
+		return true, nil
+	}
+	for i := 1; i < len(tokens)-1; i++ {
+		if sentenceParticles[tokens[i].Surface] && tokens[i-1].Known && tokens[i+1].Known {
+			return true, nil
+		}
+	}
+
+	found, err := hasWordPrefix(ctx, query, dict.db.BleveIndex)
+	return !found, err
+}
+
+// hasWordPrefix tells if some word is written as the query, or starts with it
+func hasWordPrefix(ctx context.Context, query string, index bleve.Index) (bool, error) {
+	kanjiPrefixQuery := bleve.NewPrefixQuery(query)
+	kanjiPrefixQuery.SetField("kanji_exact")
+
+	kanaPrefixQuery := bleve.NewPrefixQuery(query)
+	kanaPrefixQuery.SetField("kana_exact")
+
+	// Only the total is needed
+	searchRequest := bleve.NewSearchRequestOptions(bleve.NewDisjunctionQuery(kanjiPrefixQuery, kanaPrefixQuery), 0, 0, false)
+	searchResults, err := index.SearchInContext(ctx, searchRequest)
+	if err != nil {
+		return false, fmt.Errorf("failed to search Bleve index: %w", err)
+	}
+
+	return searchResults.Total > 0, nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/deinflect"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/segment"
+)
+
+func TestPickWord(t *testing.T) {
+	candidates := []database.Word{
+		{ID: "1", MainWord: database.Furigana{Word: "行く", Reading: "ゆく"}, Senses: []database.Sense{{PartOfSpeech: []string{"n"}}}},
+		{ID: "2", MainWord: database.Furigana{Word: "行く", Reading: "いく"}, Common: true, Senses: []database.Sense{{PartOfSpeech: []string{"v5k-s"}}}},
+	}
+
+	plain := segment.Token{Surface: "行く", Base: "行く", Known: true}
+	if word := pickWord(plain, candidates); word == nil || word.ID != "2" {
+		t.Errorf("pickWord() = %v, want the common word", word)
+	}
+
+	conjugated := segment.Token{Surface: "行きました", Base: "行く", Types: deinflect.V5, Known: true}
+	if word := pickWord(conjugated, candidates[:1]); word != nil {
+		t.Errorf("pickWord() = %v, want nil for a word that can't be conjugated", word)
+	}
+}
+
+func TestTokenReading(t *testing.T) {
+	word := database.Word{
+		MainWord:   database.Furigana{Word: "食べる", Reading: "たべる"},
+		OtherForms: []database.Furigana{{Word: "喰べる", Reading: "たべる"}},
+	}
+
+	testCases := []struct {
+		token    segment.Token
+		expected string
+	}{
+		{segment.Token{Surface: "食べる", Base: "食べる"}, "たべる"},
+		{segment.Token{Surface: "喰べた", Base: "喰べる"}, "たべた"},
+		{segment.Token{Surface: "たべた", Base: "たべる"}, ""},
+	}
+
+	for _, tc := range testCases {
+		if got := tokenReading(tc.token, word); got != tc.expected {
+			t.Errorf("tokenReading(%+v) = %q, want %q", tc.token, got, tc.expected)
+		}
+	}
+}
+
+func TestTokenSegments(t *testing.T) {
+	stored := []furigana.Span{{Text: "学", Reading: "がっ"}, {Text: "校", Reading: "こう"}}
+	word := database.Word{
+		MainWord: database.Furigana{Word: "学校", Reading: "がっこう", Segments: stored},
+	}
+
+	plain := segment.Token{Surface: "学校", Base: "学校"}
+	if got := tokenSegments(plain, word, "がっこう"); !reflect.DeepEqual(got, stored) {
+		t.Errorf("tokenSegments() = %v, want %v", got, stored)
+	}
+
+	// Conjugated tokens are aligned around their okurigana
+	conjugated := segment.Token{Surface: "食べた", Base: "食べる", Reason: "past"}
+	expected := []furigana.Span{{Text: "食", Reading: "た"}, {Text: "べた"}}
+	if got := tokenSegments(conjugated, database.Word{}, "たべた"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("tokenSegments() = %v, want %v", got, expected)
+	}
+
+	if got := tokenSegments(segment.Token{Surface: "今日", Base: "今日"}, database.Word{}, "きょう"); got != nil {
+		t.Errorf("tokenSegments() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,413 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+)
+
+const maxFuriganaTextSize = 1 << 20 // Bytes of text accepted in the body of a furigana request
+
+type APIErrorCode string
+
+const (
+	APIErrorInvalidQuery APIErrorCode = "invalid_query"
+	APIErrorNotFound     APIErrorCode = "not_found"
+	APIErrorInternal     APIErrorCode = "internal_error"
+)
+
+type APIError struct {
+	Code    APIErrorCode `json:"code"`
+	Message string       `json:"message"`
+}
+
+type APIErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+type APIPage struct {
+	Number     int `json:"number"`     // 1-based page number
+	Size       int `json:"size"`       // Max amount of results per page
+	TotalPages int `json:"totalPages"` // Amount of pages available for this query
+}
+
+type APISearchResponse struct {
+	Query   string            `json:"query"`
+	Type    SearchTermType    `json:"type"`  // Query type detected by DetectSearchTermType
+	Total   uint64            `json:"total"` // Total hits, across every page
+	Page    APIPage           `json:"page"`
+	Results []database.Word   `json:"results"`
+	Tags    map[string]string `json:"tags"` // Description of every tag used in the results
+	// Dictionary forms of a conjugated query (e.g. 食べました → 食べる), first page only
+	Inflections []Inflection `json:"inflections,omitempty"`
+	// Kanji written in a kanji query, first page only
+	Kanji []database.Kanji `json:"kanji,omitempty"`
+	// A few example sentences of the results, by word ID
+	Examples map[string][]database.Sentence `json:"examples,omitempty"`
+}
+
+type APIAnalyzeResponse struct {
+	Text   string          `json:"text"`
+	Tokens []AnalyzedToken `json:"tokens"` // Every word and piece of unknown text, in order
+}
+
+type APIFuriganaResponse struct {
+	Format    furigana.Format `json:"format"`
+	Annotated string          `json:"annotated,omitempty"` // The text with ruby markup, empty for the json format
+	Spans     []furigana.Span `json:"spans"`
+}
+
+type APINameSearchResponse struct {
+	Query   string            `json:"query"`
+	Type    SearchTermType    `json:"type"`
+	Total   uint64            `json:"total"`
+	Page    APIPage           `json:"page"`
+	Results []database.Name   `json:"results"`
+	Tags    map[string]string `json:"tags"` // Description of every name type used in the results
+}
+
+type APISentenceSearchResponse struct {
+	Query   string              `json:"query"`
+	Type    SearchTermType      `json:"type"` // English for romaji queries, Japanese otherwise
+	Total   uint64              `json:"total"`
+	Page    APIPage             `json:"page"`
+	Results []database.Sentence `json:"results"`
+}
+
+type APIKanjiSearchResponse struct {
+	Query   string           `json:"query"`
+	Type    SearchTermType   `json:"type"`
+	Total   uint64           `json:"total"`
+	Page    APIPage          `json:"page"`
+	Results []database.Kanji `json:"results"`
+}
+
+type APIKanjiResponse struct {
+	Kanji      *database.Kanji `json:"kanji"`
+	Words      []database.Word `json:"words"`      // Words written with the kanji, common ones first
+	TotalWords uint64          `json:"totalWords"` // Total words written with the kanji, not only the ones listed
+}
+
+type APIWordResponse struct {
+	Word *database.Word    `json:"word"`
+	Tags map[string]string `json:"tags"` // Description of every tag used by the word
+}
+
+type APIRadical struct {
+	Literal     string `json:"literal"`
+	StrokeCount int    `json:"strokeCount"`
+	Valid       bool   `json:"valid"` // Can be added to the selection and still match some kanji
+}
+
+type APIRadicalsResponse struct {
+	Selected []string     `json:"selected"`
+	Radicals []APIRadical `json:"radicals"`
+	Results  []KanjiGroup `json:"results"` // Matching kanji grouped by stroke count
+	Total    uint64       `json:"total"`   // Matching kanji, results are capped
+}
+
+type APITagsResponse struct {
+	Tags map[string]string `json:"tags"`
+}
+
+type APIStatsResponse struct {
+	Version    string     `json:"version"`    // JMdict version being served
+	Generation string     `json:"generation"` // Import of that version being served
+	Cache      CacheStats `json:"cache"`      // Word searches answered from memory
+}
+
+func (s *Server) apiSearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, "query parameter is required")
+		return
+	}
+
+	result, err := s.Search(r.Context(), query, parseSearchOptions(r.URL.Query(), s.config.Search))
+	if err != nil && !errors.Is(err, ErrNoResults) {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "search failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAPISearchResponse(result, s.usedTags(r.Context(), result.Words...)))
+}
+
+// newAPISearchResponse is the answer to a word search, tags describes the tags of its words
+func newAPISearchResponse(result *SearchResult, tags map[string]string) APISearchResponse {
+	response := APISearchResponse{
+		Query:   result.Query,
+		Type:    result.Type,
+		Total:   result.Total,
+		Page:    newAPIPage(result),
+		Results: result.Words,
+		Tags:    tags,
+
+		Inflections: result.Inflections,
+		Kanji:       result.Kanji,
+		Examples:    result.Examples,
+	}
+	if response.Results == nil {
+		// Always encode the list, even when it's empty
+		response.Results = []database.Word{}
+	}
+
+	return response
+}
+
+func (s *Server) apiWordHandler(w http.ResponseWriter, r *http.Request) {
+	word, err := s.lookupWord(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, ErrWordNotFound) {
+			writeAPIError(w, http.StatusNotFound, APIErrorNotFound, err.Error())
+		} else {
+			writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "lookup failed")
+		}
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIWordResponse{
+		Word: word,
+		Tags: s.usedTags(r.Context(), *word),
+	})
+}
+
+func (s *Server) apiAnalyzeHandler(w http.ResponseWriter, r *http.Request) {
+	text := strings.TrimSpace(r.URL.Query().Get("text"))
+	if text == "" {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, "text parameter is required")
+		return
+	}
+
+	analysis, err := s.analyze(r.Context(), text)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "analyze failed")
+		return
+	}
+
+	response := APIAnalyzeResponse{
+		Text:   analysis.Text,
+		Tokens: analysis.Tokens,
+	}
+	if response.Tokens == nil {
+		response.Tokens = []AnalyzedToken{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// apiFuriganaHandler annotates the 'text' parameter, or the request body when it's a POST
+func (s *Server) apiFuriganaHandler(w http.ResponseWriter, r *http.Request) {
+	text := r.URL.Query().Get("text")
+	if r.Method == http.MethodPost {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxFuriganaTextSize))
+		if err != nil {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, APIErrorInvalidQuery, "text is too long")
+			return
+		}
+		text = string(body)
+	}
+
+	if strings.TrimSpace(text) == "" {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, "text is required")
+		return
+	}
+
+	format, err := furigana.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, err.Error())
+		return
+	}
+
+	options, err := parseFuriganaOptions(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, err.Error())
+		return
+	}
+
+	spans, err := s.Annotate(r.Context(), text, options)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "annotation failed")
+		return
+	}
+
+	response := APIFuriganaResponse{
+		Format: format,
+		Spans:  spans,
+	}
+	switch format {
+	case furigana.HTMLFormat:
+		response.Annotated = furigana.HTML(spans)
+	case furigana.AnkiFormat:
+		response.Annotated = furigana.Anki(spans)
+	}
+	if response.Spans == nil {
+		response.Spans = []furigana.Span{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) apiNamesSearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, "query parameter is required")
+		return
+	}
+
+	result, err := s.searchNames(r.Context(), query, parseSearchOptions(r.URL.Query(), s.config.Search))
+	if err != nil && !errors.Is(err, ErrNoResults) {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "search failed")
+		return
+	}
+
+	response := APINameSearchResponse{
+		Query:   result.Query,
+		Type:    result.Type,
+		Total:   result.Total,
+		Page:    newAPIPage(result),
+		Results: result.Names,
+		Tags:    s.usedNameTags(r.Context(), result.Names...),
+	}
+	if response.Results == nil {
+		response.Results = []database.Name{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) apiSentencesSearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, "query parameter is required")
+		return
+	}
+
+	result, err := s.searchSentences(r.Context(), query, parseSearchOptions(r.URL.Query(), s.config.Search))
+	if err != nil && !errors.Is(err, ErrNoResults) {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "search failed")
+		return
+	}
+
+	response := APISentenceSearchResponse{
+		Query:   result.Query,
+		Type:    result.Type,
+		Total:   result.Total,
+		Page:    newAPIPage(result),
+		Results: result.Sentences,
+	}
+	if response.Results == nil {
+		response.Results = []database.Sentence{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) apiKanjiSearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		writeAPIError(w, http.StatusBadRequest, APIErrorInvalidQuery, "query parameter is required")
+		return
+	}
+
+	result, err := s.searchKanji(r.Context(), query, parseSearchOptions(r.URL.Query(), s.config.Search))
+	if err != nil && !errors.Is(err, ErrNoResults) {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "search failed")
+		return
+	}
+
+	response := APIKanjiSearchResponse{
+		Query:   result.Query,
+		Type:    result.Type,
+		Total:   result.Total,
+		Page:    newAPIPage(result),
+		Results: result.Kanji,
+	}
+	if response.Results == nil {
+		response.Results = []database.Kanji{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) apiKanjiHandler(w http.ResponseWriter, r *http.Request) {
+	data, err := s.lookupKanji(r.Context(), r.PathValue("char"))
+	if err != nil {
+		if errors.Is(err, ErrKanjiNotFound) {
+			writeAPIError(w, http.StatusNotFound, APIErrorNotFound, err.Error())
+		} else {
+			writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "lookup failed")
+		}
+
+		return
+	}
+
+	response := APIKanjiResponse{
+		Kanji:      data.Kanji,
+		Words:      data.Words,
+		TotalWords: data.TotalWords,
+	}
+	if response.Words == nil {
+		response.Words = []database.Word{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) apiRadicalsHandler(w http.ResponseWriter, r *http.Request) {
+	selected := parseRadicals(r.URL.Query(), s.dictionaryFor(r.Context()).radicals)
+	pick, err := s.pickRadicals(r.Context(), selected)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, APIErrorInternal, "radical lookup failed")
+		return
+	}
+
+	response := APIRadicalsResponse{
+		Selected: pick.Selected,
+		Radicals: make([]APIRadical, 0, len(pick.Radicals)),
+		Results:  pick.Groups,
+		Total:    pick.Total,
+	}
+	for _, option := range pick.Radicals {
+		response.Radicals = append(response.Radicals, APIRadical{
+			Literal:     option.Literal,
+			StrokeCount: option.StrokeCount,
+			Valid:       option.Valid,
+		})
+	}
+	if response.Selected == nil {
+		response.Selected = []string{}
+	}
+	if response.Results == nil {
+		response.Results = []KanjiGroup{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) apiTagsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, APITagsResponse{Tags: s.dictionaryFor(r.Context()).tags})
+}
+
+func (s *Server) apiStatsHandler(w http.ResponseWriter, r *http.Request) {
+	release := s.dictionaryFor(r.Context()).release
+	writeJSON(w, http.StatusOK, APIStatsResponse{
+		Version:    release.JmdictVersion,
+		Generation: release.Generation,
+		Cache:      s.cache.stats(),
+	})
+}
+
+func newAPIPage(result *SearchResult) APIPage {
+	pager := newPager(result)
+
+	return APIPage{
+		Number:     pager.Page,
+		Size:       pager.Size,
+		TotalPages: pager.TotalPages,
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+)
+
+// failingStore fails word lookups, like a MongoDB that went away
+type failingStore struct {
+	database.Store
+}
+
+func (*failingStore) GetWord(ctx context.Context, id string) (*database.Word, error) {
+	return nil, errors.New("connection reset")
+}
+
+// serveAPI answers a single request, and returns its status and body
+func serveAPI(t *testing.T, s *Server, target string) (int, string) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	s.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("%s: Content-Type = %q, want JSON", target, contentType)
+	}
+
+	return w.Code, strings.TrimSpace(w.Body.String())
+}
+
+func TestAPIHandlers(t *testing.T) {
+	s := testServer(t, []database.Tag{{Name: "v1", Description: "Ichidan verb"}, {Name: "n", Description: "noun"}},
+		database.Word{ID: "1", MainWord: database.Furigana{Word: "食べる", Reading: "たべる"}, Meanings: []string{"to eat"},
+			Senses: []database.Sense{{PartOfSpeech: []string{"v1"}, Glosses: []string{"to eat"}}}},
+	)
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		want   string // Part of the body
+	}{
+		{"word", "/api/v1/words/1", http.StatusOK, `"tags":{"v1":"Ichidan verb"}`},
+		{"unknown word", "/api/v1/words/2", http.StatusNotFound, `{"error":{"code":"not_found",`},
+		{"tags", "/api/v1/tags", http.StatusOK, `{"tags":{"n":"noun","v1":"Ichidan verb"}}`},
+		{"stats", "/api/v1/stats", http.StatusOK, `"version":"3.6.1","generation":"` + s.dictionary().release.Generation + `"`},
+		{"no results", "/api/v1/search?query=zzzz", http.StatusOK, `"total":0,`},
+		{"no results list", "/api/v1/search?query=zzzz", http.StatusOK, `"results":[],`},
+		{"missing query", "/api/v1/search", http.StatusBadRequest, `{"error":{"code":"invalid_query",`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := serveAPI(t, s, tt.target)
+			if status != tt.status || !strings.Contains(body, tt.want) {
+				t.Errorf("status %d, body %s, want %d and a body containing %s", status, body, tt.status, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIEmptyRelease(t *testing.T) {
+	s := testServer(t, nil)
+
+	// Lists and maps are encoded empty, never null
+	if status, body := serveAPI(t, s, "/api/v1/tags"); status != http.StatusOK || body != `{"tags":{}}` {
+		t.Errorf("tags: status %d, body %s, want an empty map", status, body)
+	}
+	if _, body := serveAPI(t, s, "/api/v1/search?query=eat"); !strings.Contains(body, `"results":[]`) {
+		t.Errorf("search: body %s, want an empty results list", body)
+	}
+}
+
+func TestAPIWordHandlerStoreError(t *testing.T) {
+	s := testServer(t, nil)
+	db := s.dictionary().db
+	db.Store = &failingStore{Store: db.Store}
+
+	status, body := serveAPI(t, s, "/api/v1/words/1")
+	if want := `{"error":{"code":"internal_error","message":"lookup failed"}}`; status != http.StatusInternalServerError || body != want {
+		t.Errorf("status %d, body %s, want 500 and %s", status, body, want)
+	}
+}
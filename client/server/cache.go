@@ -0,0 +1,157 @@
+package server
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// searchCache keeps the latest search results of a dictionary version. Concurrent searches of the
+// same query wait for a single backend call instead of running their own. Cached results are shared
+// between requests, so they must not be modified.
+type searchCache struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	version string                   // Dictionary the entries were searched in, they're dropped when it changes
+	entries map[string]*list.Element // Cache key to its element in order
+	order   *list.List               // Most recently used first
+
+	group  singleflight.Group
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type cacheEntry struct {
+	key     string
+	result  *SearchResult
+	err     error // Only ErrNoResults, any other error is retried on the next request
+	expires time.Time
+}
+
+// CacheStats tells how well the search cache is doing
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	Size    int    `json:"size"` // Maximum number of entries
+}
+
+func newSearchCache(size int, ttl time.Duration) *searchCache {
+	return &searchCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// searchCacheKey identifies a page of results. The caller passes the term already normalized with
+// normalizeSearchTerm, so equivalent queries share an entry.
+func searchCacheKey(searchTerm string, options SearchOptions) string {
+	return fmt.Sprintf("%s\x00%d\x00%d", searchTerm, options.Page, options.Size)
+}
+
+// get returns the cached result of the key, or loads it. Loads of the same key and version run only once at a time.
+func (c *searchCache) get(version string, key string, load func() (*SearchResult, error)) (*SearchResult, error) {
+	if entry, ok := c.lookup(version, key); ok {
+		c.hits.Add(1)
+		return entry.result, entry.err
+	}
+	c.misses.Add(1)
+
+	value, err, _ := c.group.Do(version+"\x00"+key, func() (any, error) {
+		// Another request may have stored it in the meantime
+		if entry, ok := c.lookup(version, key); ok {
+			return entry.result, entry.err
+		}
+
+		result, err := load()
+		if err == nil || errors.Is(err, ErrNoResults) {
+			c.store(version, key, result, err)
+		}
+		return result, err
+	})
+
+	result, _ := value.(*SearchResult)
+	return result, err
+}
+
+func (c *searchCache) lookup(version string, key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkVersion(version)
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if c.now().After(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry, true
+}
+
+func (c *searchCache) store(version string, key string, result *SearchResult, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The dictionary was swapped while searching, the result belongs to the old one
+	if c.version != version {
+		return
+	}
+
+	entry := &cacheEntry{key: key, result: result, err: err, expires: c.now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// checkVersion drops every entry when another dictionary is being searched. Callers hold the lock.
+func (c *searchCache) checkVersion(version string) {
+	if c.version == version {
+		return
+	}
+
+	c.version = version
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *searchCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+func (c *searchCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: c.order.Len(),
+		Size:    c.size,
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSearchCacheHitsAndEviction(t *testing.T) {
+	cache := newSearchCache(2, time.Minute)
+	loads := 0
+	load := func() (*SearchResult, error) {
+		loads++
+		return &SearchResult{}, nil
+	}
+
+	cache.get("v1", "a", load)
+	cache.get("v1", "a", load)
+	cache.get("v1", "b", load)
+	cache.get("v1", "c", load) // Drops "a", the least recently used
+	cache.get("v1", "a", load)
+
+	if loads != 4 {
+		t.Errorf("loads = %d, want 4", loads)
+	}
+	if stats := cache.stats(); stats.Hits != 1 || stats.Misses != 4 || stats.Entries != 2 {
+		t.Errorf("stats() = %+v, want 1 hit, 4 misses and 2 entries", stats)
+	}
+}
+
+func TestSearchCacheExpiresAndVersions(t *testing.T) {
+	now := time.Now()
+	cache := newSearchCache(10, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() (*SearchResult, error) {
+		loads++
+		return &SearchResult{}, ErrNoResults
+	}
+
+	if _, err := cache.get("v1", "a", load); !errors.Is(err, ErrNoResults) {
+		t.Errorf("get() error = %v, want ErrNoResults", err)
+	}
+	// Queries without results are cached too
+	if _, err := cache.get("v1", "a", load); !errors.Is(err, ErrNoResults) || loads != 1 {
+		t.Errorf("get() error = %v after %d loads, want a cached ErrNoResults", err, loads)
+	}
+
+	now = now.Add(2 * time.Minute)
+	cache.get("v1", "a", load)
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2 once the entry expired", loads)
+	}
+
+	cache.get("v2", "a", load)
+	if loads != 3 {
+		t.Errorf("loads = %d, want 3 after the version changed", loads)
+	}
+}
+
+func TestSearchCacheSkipsErrors(t *testing.T) {
+	cache := newSearchCache(10, time.Minute)
+	failure := errors.New("store is down")
+
+	loads := 0
+	load := func() (*SearchResult, error) {
+		loads++
+		return nil, failure
+	}
+
+	cache.get("v1", "a", load)
+	if _, err := cache.get("v1", "a", load); err != failure || loads != 2 {
+		t.Errorf("get() error = %v after %d loads, want failures to be retried", err, loads)
+	}
+}
+
+func TestSearchCacheCollapsesConcurrentLoads(t *testing.T) {
+	cache := newSearchCache(10, time.Minute)
+	release := make(chan struct{})
+	var loads atomic.Int32
+
+	load := func() (*SearchResult, error) {
+		loads.Add(1)
+		<-release
+		return &SearchResult{}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.get("v1", "a", load)
+		}()
+	}
+
+	// Let every request reach the cache before the first load finishes
+	for cache.stats().Misses < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("loads = %d, want 1", got)
+	}
+}
+
+func TestSearchCacheKey(t *testing.T) {
+	options := SearchOptions{Page: 1, Size: 20}
+	if normalizeSearchTerm("  Eat  Bread ") != "eat bread" {
+		t.Errorf("normalizeSearchTerm() = %q, want %q", normalizeSearchTerm("  Eat  Bread "), "eat bread")
+	}
+	if searchCacheKey("eat", options) == searchCacheKey("eat", SearchOptions{Page: 2, Size: 20}) {
+		t.Error("searchCacheKey() is the same for different pages")
+	}
+}
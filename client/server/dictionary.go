@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/segment"
+	"github.com/izquiratops/tango/common/types"
+)
+
+const releaseCheckInterval = 10 * time.Second // How often the 'current' release pointer is checked
+
+// dictionary is everything loaded from one release. It's swapped as a whole
+// when a new import is promoted, so a request always sees a single release.
+type dictionary struct {
+	db       *database.Database
+	tags     map[string]string  // Tag name to description
+	radicals []database.Radical // Every radical of the picker, sorted by stroke count
+	forms    segment.Forms      // Every written form of the words, used to split sentences
+	release  database.Release
+	manifest database.Manifest // Import stats of the release, exposed as metrics
+
+	mu      sync.Mutex
+	users   int  // Requests holding the release, see Server.acquire
+	retired bool // Replaced by a newer release, it's closed once no request holds it
+}
+
+type dictionaryKey struct{}
+
+func openDictionary(ctx context.Context, config *types.ServerConfig, release database.Release, metrics *metrics) (*dictionary, error) {
+	db, err := database.OpenDatabase(ctx, config, release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	if release.Legacy() {
+		slog.Warn("Serving an index from an older importer, only words can be searched until the next import", "path", release.BlevePath())
+	}
+	metrics.instrument(db, config.StorageBackend)
+
+	tags, err := fetchTags(db)
+	if err != nil {
+		db.Close(context.Background())
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	radicals, err := db.Store.GetRadicals(context.Background())
+	if err != nil {
+		db.Close(context.Background())
+		return nil, fmt.Errorf("failed to load radicals: %w", err)
+	}
+
+	forms, err := loadForms(db.BleveIndex)
+	if err != nil {
+		db.Close(context.Background())
+		return nil, fmt.Errorf("failed to load word forms: %w", err)
+	}
+
+	// Only the metrics need it, a missing manifest doesn't stop the release from being served
+	manifest, err := release.ReadManifest()
+	if err != nil {
+		slog.Warn("Failed to read release manifest", "generation", release.Generation, "error", err)
+	}
+
+	return &dictionary{
+		db:       db,
+		tags:     tags,
+		radicals: sortRadicals(radicals),
+		forms:    forms,
+		release:  release,
+		manifest: manifest,
+	}, nil
+}
+
+// version tells releases apart, even two imports of the same JMdict version
+func (d *dictionary) version() string {
+	return d.release.JmdictVersion + "/" + d.release.Generation
+}
+
+// dictionary returns the release currently being served
+func (s *Server) dictionary() *dictionary {
+	return s.dict.Load()
+}
+
+// dictionaryFor returns the release held by the request, so it uses a single release from start
+// to end. Outside of requests it's the release currently being served.
+func (s *Server) dictionaryFor(ctx context.Context) *dictionary {
+	if dict, ok := ctx.Value(dictionaryKey{}).(*dictionary); ok {
+		return dict
+	}
+	return s.dictionary()
+}
+
+// holdDictionary keeps the release being served open until the request is done
+func (s *Server) holdDictionary(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dict := s.acquire()
+		defer s.release(dict)
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), dictionaryKey{}, dict)))
+	})
+}
+
+// acquire returns the release being served, it stays open until it's released
+func (s *Server) acquire() *dictionary {
+	for {
+		dict := s.dictionary()
+
+		dict.mu.Lock()
+		if !dict.retired {
+			dict.users++
+			dict.mu.Unlock()
+			return dict
+		}
+		// Replaced in between, the new release is already being served
+		dict.mu.Unlock()
+	}
+}
+
+// release lets go of an acquired release, the last request using a replaced release closes it
+func (s *Server) release(dict *dictionary) {
+	dict.mu.Lock()
+	dict.users--
+	unused := dict.retired && dict.users == 0
+	dict.mu.Unlock()
+
+	if unused {
+		s.closeRetired(dict)
+	}
+}
+
+// watchReleases swaps the served dictionary whenever the importer promotes a new release,
+// or a rollback goes back to the previous one
+func (s *Server) watchReleases() {
+	defer s.watching.Done()
+
+	ticker := time.NewTicker(releaseCheckInterval)
+	defer ticker.Stop()
+
+	// Opening a release waits for MongoDB, closing the server shouldn't wait for it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		release, err := database.CurrentRelease(s.config.DataDir, s.config.JmdictVersion)
+		if err != nil {
+			if !errors.Is(err, database.ErrNoRelease) {
+				slog.Error("Failed to check current release", "error", err)
+			}
+			continue
+		}
+
+		if release == s.dictionary().release {
+			continue
+		}
+
+		// Failures are retried on the next tick, e.g. a bolt file still held by a release closing down
+		if err := s.switchRelease(ctx, release); err != nil {
+			slog.Error("Failed to switch release", "generation", release.Generation, "error", err)
+		}
+	}
+}
+
+func (s *Server) switchRelease(ctx context.Context, release database.Release) error {
+	dict, err := openDictionary(ctx, &s.config, release, s.metrics)
+	if err != nil {
+		return err
+	}
+
+	old := s.dict.Swap(dict)
+	slog.Info("Serving release", "generation", release.Generation, "tags", len(dict.tags))
+
+	s.retire(old)
+
+	return nil
+}
+
+// retire closes a replaced dictionary once the requests holding it are done, right away if there are none
+func (s *Server) retire(old *dictionary) {
+	s.retiredMu.Lock()
+	s.retired[old] = struct{}{}
+	s.retiredMu.Unlock()
+
+	old.mu.Lock()
+	old.retired = true
+	unused := old.users == 0
+	old.mu.Unlock()
+
+	if unused {
+		s.closeRetired(old)
+	}
+}
+
+// closeRetired closes a replaced release, unless Close already did
+func (s *Server) closeRetired(old *dictionary) {
+	s.retiredMu.Lock()
+	_, open := s.retired[old]
+	delete(s.retired, old)
+	s.retiredMu.Unlock()
+
+	if !open {
+		return
+	}
+	if err := old.db.Close(context.Background()); err != nil {
+		slog.Error("Failed to close release", "generation", old.release.Generation, "error", err)
+	}
+}
+
+// Close stops watching for new releases and closes every release still open, flushing the
+// Bleve indexes and disconnecting from the store. Requests must be done by then.
+func (s *Server) Close(ctx context.Context) error {
+	close(s.stop)
+	// A release being switched to has to be open before it can be closed
+	s.watching.Wait()
+
+	var errs []error
+
+	// Replaced releases still held by a request that outlived the shutdown are closed anyway
+	s.retiredMu.Lock()
+	for old := range s.retired {
+		errs = append(errs, old.db.Close(ctx))
+		delete(s.retired, old)
+	}
+	s.retiredMu.Unlock()
+
+	errs = append(errs, s.dictionary().db.Close(ctx))
+
+	return errors.Join(errs...)
+}
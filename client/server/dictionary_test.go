@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+)
+
+type closingStore struct {
+	database.Store
+	closed bool
+}
+
+func (s *closingStore) Ping(ctx context.Context) error {
+	if s.closed {
+		return errors.New("store is closed")
+	}
+	return nil
+}
+
+func (s *closingStore) Close(ctx context.Context) error {
+	s.closed = true
+	return nil
+}
+
+func testDictionary(t *testing.T, generation string) (*dictionary, *closingStore) {
+	t.Helper()
+
+	db := &database.Database{Store: &closingStore{}}
+	for _, index := range []*bleve.Index{&db.BleveIndex, &db.KanjiIndex, &db.NamesIndex, &db.SentencesIndex} {
+		var err error
+		if *index, err = bleve.NewMemOnly(bleve.NewIndexMapping()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	release := database.Release{JmdictVersion: "3.6.1", Generation: generation}
+	return &dictionary{db: db, release: release}, db.Store.(*closingStore)
+}
+
+func TestServerClose(t *testing.T) {
+	s := &Server{
+		stop:    make(chan struct{}),
+		retired: make(map[*dictionary]struct{}),
+	}
+	old, oldStore := testDictionary(t, "1")
+	s.dict.Store(old)
+	// A request that outlived the shutdown still holds the replaced release
+	s.acquire()
+
+	current, currentStore := testDictionary(t, "2")
+	s.dict.Store(current)
+	s.retire(old)
+
+	s.watching.Add(1)
+	go s.watchReleases()
+
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	if !currentStore.closed || !oldStore.closed {
+		t.Errorf("stores closed: current %v, replaced %v, want both", currentStore.closed, oldStore.closed)
+	}
+	if _, err := old.db.BleveIndex.DocCount(); err == nil {
+		t.Error("index of the replaced release is still open")
+	}
+	if len(s.retired) != 0 {
+		t.Errorf("%d releases still waiting to be closed", len(s.retired))
+	}
+}
+
+func TestRetireWaitsForRequests(t *testing.T) {
+	s := &Server{retired: make(map[*dictionary]struct{})}
+	old, oldStore := testDictionary(t, "1")
+	s.dict.Store(old)
+
+	held := s.acquire()
+	if held != old {
+		t.Fatalf("acquire() = release %s, want 1", held.release.Generation)
+	}
+
+	current, _ := testDictionary(t, "2")
+	s.dict.Store(current)
+	s.retire(old)
+
+	if oldStore.closed {
+		t.Fatal("replaced release closed while a request holds it")
+	}
+	if dict := s.acquire(); dict != current {
+		t.Errorf("acquire() = release %s after the swap, want 2", dict.release.Generation)
+	} else {
+		s.release(dict)
+	}
+
+	s.release(held)
+	if !oldStore.closed {
+		t.Error("replaced release still open once its last request is done")
+	}
+	if len(s.retired) != 0 {
+		t.Errorf("%d releases still waiting to be closed", len(s.retired))
+	}
+
+	// A release nobody holds is closed as soon as it's replaced
+	next, _ := testDictionary(t, "3")
+	s.dict.Store(next)
+	s.retire(current)
+	if _, err := current.db.BleveIndex.DocCount(); err == nil {
+		t.Error("index of the unused replaced release is still open")
+	}
+}
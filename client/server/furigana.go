@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"unicode"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/segment"
+)
+
+// FuriganaOptions picks the words left without reading, the ones readers already know
+type FuriganaOptions struct {
+	SkipCommon bool // Leave common words as they are
+	// Leave words whose kanji are all at this JLPT level or easier as they are. It's the old 4 (easiest)
+	// to 1 scale of Kanjidic2, 0 annotates every word.
+	SkipJLPT int
+}
+
+// parseFuriganaOptions reads 'skipCommon' and 'skipJlpt' from the query string
+func parseFuriganaOptions(values url.Values) (FuriganaOptions, error) {
+	var options FuriganaOptions
+
+	if value := values.Get("skipCommon"); value != "" {
+		skipCommon, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, fmt.Errorf("skipCommon must be true or false")
+		}
+		options.SkipCommon = skipCommon
+	}
+
+	if value := values.Get("skipJlpt"); value != "" {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 0 || level > 4 {
+			return options, fmt.Errorf("skipJlpt must be a level from 1 to 4, or 0")
+		}
+		options.SkipJLPT = level
+	}
+
+	return options, nil
+}
+
+// Annotate splits the text into spans, giving a reading to every word written with kanji.
+// Joining the text of every span gives the text back.
+func (s *Server) Annotate(ctx context.Context, text string, options FuriganaOptions) ([]furigana.Span, error) {
+	// Words and kanji levels come from the same release, even if it's replaced meanwhile
+	dict := s.dictionaryFor(ctx)
+	return annotate(ctx, text, options, dict.db, dict.forms)
+}
+
+// annotate is Annotate on one release, its words split the text and its kanji give the JLPT levels
+func annotate(ctx context.Context, text string, options FuriganaOptions, db *database.Database, forms segment.Forms) ([]furigana.Span, error) {
+	analysis, err := analyzeText(ctx, text, db, forms)
+	if err != nil {
+		return nil, err
+	}
+
+	var levels map[rune]int
+	if options.SkipJLPT > 0 {
+		if levels, err = jlptLevels(ctx, analysis.Tokens, db); err != nil {
+			slog.ErrorContext(ctx, "Failed to fetch kanji from store", "error", err)
+			return nil, err
+		}
+	}
+
+	var spans []furigana.Span
+	for _, token := range analysis.Tokens {
+		if token.Reading == "" || options.skips(token, levels) {
+			spans = furigana.Append(spans, furigana.Span{Text: token.Surface})
+			continue
+		}
+
+		// Every kanji gets its own reading when the token could be aligned
+		for _, span := range rubySpans(token.Furigana()) {
+			spans = furigana.Append(spans, span)
+		}
+	}
+
+	return spans, nil
+}
+
+// rubySpans returns the aligned segments of the form, or the whole form with its reading when it couldn't be aligned
+func rubySpans(form database.Furigana) []furigana.Span {
+	if form.Segments != nil {
+		return form.Segments
+	}
+
+	return []furigana.Span{{Text: form.Word, Reading: form.Reading}}
+}
+
+// ruby writes the form in furigana for the templates
+func ruby(form database.Furigana) template.HTML {
+	return template.HTML(furigana.HTML(rubySpans(form)))
+}
+
+func (o FuriganaOptions) skips(token AnalyzedToken, levels map[rune]int) bool {
+	if o.SkipCommon && token.Word != nil && token.Word.Common {
+		return true
+	}
+
+	if o.SkipJLPT == 0 {
+		return false
+	}
+
+	for _, r := range token.Surface {
+		// Kanji without level are outside the JLPT lists, so they're never known
+		if unicode.Is(unicode.Han, r) && levels[r] < o.SkipJLPT {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jlptLevels returns the JLPT level of every kanji in the tokens, kanji without level are left out
+func jlptLevels(ctx context.Context, tokens []AnalyzedToken, db *database.Database) (map[rune]int, error) {
+	var literals []string
+	seen := make(map[rune]bool)
+
+	for _, token := range tokens {
+		for _, r := range token.Surface {
+			if unicode.Is(unicode.Han, r) && !seen[r] {
+				seen[r] = true
+				literals = append(literals, string(r))
+			}
+		}
+	}
+
+	if len(literals) == 0 {
+		return nil, nil
+	}
+
+	kanji, err := db.Store.GetKanji(ctx, literals)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[rune]int, len(kanji))
+	for _, k := range kanji {
+		if k.JLPT > 0 {
+			levels[[]rune(k.Literal)[0]] = k.JLPT
+		}
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+)
+
+func TestParseFuriganaOptions(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected FuriganaOptions
+		valid    bool
+	}{
+		{"", FuriganaOptions{}, true},
+		{"skipCommon=true&skipJlpt=3", FuriganaOptions{SkipCommon: true, SkipJLPT: 3}, true},
+		{"skipCommon=maybe", FuriganaOptions{}, false},
+		{"skipJlpt=5", FuriganaOptions{}, false},
+	}
+
+	for _, tc := range testCases {
+		values, _ := url.ParseQuery(tc.query)
+		options, err := parseFuriganaOptions(values)
+		if (err == nil) != tc.valid || (tc.valid && options != tc.expected) {
+			t.Errorf("parseFuriganaOptions(%q) = %+v, %v", tc.query, options, err)
+		}
+	}
+}
+
+func TestFuriganaOptionsSkips(t *testing.T) {
+	common := &database.Word{Common: true}
+	levels := map[rune]int{'食': 4, '学': 4, '校': 3}
+
+	testCases := []struct {
+		options  FuriganaOptions
+		token    AnalyzedToken
+		expected bool
+	}{
+		{FuriganaOptions{}, AnalyzedToken{Surface: "食べる", Word: common}, false},
+		{FuriganaOptions{SkipCommon: true}, AnalyzedToken{Surface: "食べる", Word: common}, true},
+		{FuriganaOptions{SkipCommon: true}, AnalyzedToken{Surface: "食べる", Word: &database.Word{}}, false},
+		{FuriganaOptions{SkipJLPT: 4}, AnalyzedToken{Surface: "食べる"}, true},
+		{FuriganaOptions{SkipJLPT: 4}, AnalyzedToken{Surface: "学校"}, false},
+		{FuriganaOptions{SkipJLPT: 3}, AnalyzedToken{Surface: "学校"}, true},
+		{FuriganaOptions{SkipJLPT: 1}, AnalyzedToken{Surface: "鬱"}, false},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.options.skips(tc.token, levels); got != tc.expected {
+			t.Errorf("%+v skips %q = %v, want %v", tc.options, tc.token.Surface, got, tc.expected)
+		}
+	}
+}
+
+func TestRuby(t *testing.T) {
+	aligned := database.Furigana{
+		Word:     "取り扱い",
+		Reading:  "とりあつかい",
+		Segments: []furigana.Span{{Text: "取", Reading: "と"}, {Text: "り"}, {Text: "扱", Reading: "あつか"}, {Text: "い"}},
+	}
+	expected := "<ruby>取<rp>(</rp><rt>と</rt><rp>)</rp></ruby>り<ruby>扱<rp>(</rp><rt>あつか</rt><rp>)</rp></ruby>い"
+	if got := string(ruby(aligned)); got != expected {
+		t.Errorf("ruby() = %q, want %q", got, expected)
+	}
+
+	// Forms that couldn't be aligned keep the whole reading
+	whole := database.Furigana{Word: "今日", Reading: "きょう"}
+	expected = "<ruby>今日<rp>(</rp><rt>きょう</rt><rp>)</rp></ruby>"
+	if got := string(ruby(whole)); got != expected {
+		t.Errorf("ruby() = %q, want %q", got, expected)
+	}
+}
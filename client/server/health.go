@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	healthRoute    = "GET /healthz"
+	readyRoute     = "GET /readyz"
+	readyCheckTime = 2 * time.Second // A store slower than this is as good as down for the searches
+)
+
+type APIHealthResponse struct {
+	Status string `json:"status"` // Always "ok", a dead process doesn't answer
+}
+
+// APIReadyResponse tells whether the release being served can answer searches, and which one it is
+type APIReadyResponse struct {
+	Ready      bool         `json:"ready"`
+	Version    string       `json:"version"`    // JMdict version being served
+	Generation string       `json:"generation"` // Import of that version being served
+	DictDate   string       `json:"dictDate"`   // Date of the JMdict file, empty if the release has no manifest
+	Checks     []ReadyCheck `json:"checks"`
+}
+
+type ReadyCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// healthHandler answers as long as the process is up, it doesn't look at the dependencies
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, APIHealthResponse{Status: "ok"})
+}
+
+// readyHandler checks the release being served, answering 503 if any check fails so the
+// proxy and the compose healthcheck stop sending it traffic
+func (s *Server) readyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyCheckTime)
+	defer cancel()
+
+	response := s.readiness(ctx)
+
+	status := http.StatusOK
+	if !response.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, response)
+}
+
+func (s *Server) readiness(ctx context.Context) APIReadyResponse {
+	dict := s.dictionaryFor(ctx)
+	response := APIReadyResponse{
+		Version:    dict.release.JmdictVersion,
+		Generation: dict.release.Generation,
+		DictDate:   dict.manifest.DictDate,
+	}
+
+	check := func(name string, err error) {
+		result := ReadyCheck{Name: name, OK: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		response.Checks = append(response.Checks, result)
+	}
+
+	check("release", func() error {
+		if dict.release.JmdictVersion == "" || dict.release.Generation == "" {
+			return errors.New("no release loaded")
+		}
+		return nil
+	}())
+
+	check("store", dict.db.Store.Ping(ctx))
+
+	check("words_index", func() error {
+		count, err := dict.db.BleveIndex.DocCount()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("index is empty")
+		}
+		return nil
+	}())
+
+	response.Ready = true
+	for _, result := range response.Checks {
+		response.Ready = response.Ready && result.OK
+	}
+
+	return response
+}
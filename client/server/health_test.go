@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyHandler(t *testing.T) {
+	dict, store := testDictionary(t, "1")
+	dict.manifest.DictDate = "2025-01-01"
+	s := &Server{}
+	s.dict.Store(dict)
+
+	ready := func() (int, APIReadyResponse) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		s.readyHandler(w, httptest.NewRequest("GET", "/readyz", nil))
+
+		var response APIReadyResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, response
+	}
+
+	// Nothing imported yet
+	if code, response := ready(); code != http.StatusServiceUnavailable || response.Ready {
+		t.Errorf("empty index: status %d, ready %v, want 503 and not ready", code, response.Ready)
+	}
+
+	if err := dict.db.BleveIndex.Index("1", map[string]string{"kana": "たべる"}); err != nil {
+		t.Fatal(err)
+	}
+	code, response := ready()
+	if code != http.StatusOK || !response.Ready {
+		t.Errorf("status %d, ready %v, want 200 and ready (%+v)", code, response.Ready, response.Checks)
+	}
+	if response.Version != "3.6.1" || response.Generation != "1" || response.DictDate != "2025-01-01" {
+		t.Errorf("release = %q %q %q", response.Version, response.Generation, response.DictDate)
+	}
+
+	store.closed = true
+	code, response = ready()
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("closed store: status %d, want 503", code)
+	}
+	for _, check := range response.Checks {
+		if check.OK == (check.Name == "store") {
+			t.Errorf("closed store: check %q ok = %v", check.Name, check.OK)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/deinflect"
+)
+
+const (
+	// Keeps the Bleve disjunction far below its clause limit
+	maxInflectionForms = 100
+	maxInflectionWords = 20
+)
+
+type Inflection struct {
+	Base   string        `json:"base"`   // Dictionary form the query was deinflected to
+	Reason string        `json:"reason"` // Applied inflections (e.g. "polite past")
+	Word   database.Word `json:"word"`
+}
+
+// findInflections deinflects a kana/kanji query and returns the dictionary words it could be
+// a conjugation of. Candidates are only kept when the part of speech of the word allows them.
+func findInflections(ctx context.Context, searchTerm string, db *database.Database) ([]Inflection, error) {
+	candidates := deinflect.Deinflect(searchTerm)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var forms []string
+	seenForms := make(map[string]bool)
+	for _, candidate := range candidates {
+		if !seenForms[candidate.Term] && len(forms) < maxInflectionForms {
+			seenForms[candidate.Term] = true
+			forms = append(forms, candidate.Term)
+		}
+	}
+
+	ids, err := performExactQuery(ctx, forms, maxInflectionWords, db)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	words, err := fetchWordsByIDs(ctx, ids, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var inflections []Inflection
+	seenWords := make(map[string]bool)
+
+	// Candidates come out shortest chain first, so simpler explanations win
+	for _, candidate := range candidates {
+		for _, word := range words {
+			if seenWords[word.ID] || !hasForm(word, candidate.Term) || !hasPartOfSpeech(word, candidate.Types) {
+				continue
+			}
+
+			seenWords[word.ID] = true
+			inflections = append(inflections, Inflection{
+				Base:   candidate.Term,
+				Reason: candidate.Reason(),
+				Word:   word,
+			})
+		}
+	}
+
+	return inflections, nil
+}
+
+func hasForm(word database.Word, form string) bool {
+	furiganas := append([]database.Furigana{word.MainWord}, word.OtherForms...)
+
+	for _, furigana := range furiganas {
+		if furigana.Word == form || furigana.Reading == form {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPartOfSpeech(word database.Word, types deinflect.WordType) bool {
+	for _, sense := range word.Senses {
+		for _, pos := range sense.PartOfSpeech {
+			if types.MatchesPartOfSpeech(pos) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/kana"
+)
+
+const kanjiWordsSize = 50 // Words listed on a kanji page
+
+// ErrKanjiNotFound is returned when looking up a character that isn't in Kanjidic2
+var ErrKanjiNotFound = errors.New("kanji not found")
+
+type KanjiData struct {
+	Kanji      *database.Kanji
+	Words      []database.Word // Words written with the kanji, common ones first
+	TotalWords uint64
+}
+
+// lookupKanji returns a kanji and the words that contain it
+func (s *Server) lookupKanji(ctx context.Context, literal string) (*KanjiData, error) {
+	if utf8.RuneCountInString(literal) != 1 || !isKanji([]rune(literal)[0]) {
+		return nil, ErrKanjiNotFound
+	}
+
+	db := s.dictionaryFor(ctx).db
+
+	kanji, err := db.Store.GetKanjiCharacter(ctx, literal)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, ErrKanjiNotFound
+		}
+		slog.ErrorContext(ctx, "Failed to fetch kanji from store", "error", err)
+		return nil, err
+	}
+
+	ids, total, err := performContainsQuery(ctx, literal, kanjiWordsSize, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to run Bleve query", "error", err)
+		return nil, err
+	}
+
+	words, err := fetchWordsByIDs(ctx, ids, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch words from store", "error", err)
+		return nil, err
+	}
+
+	return &KanjiData{
+		Kanji:      kanji,
+		Words:      words,
+		TotalWords: total,
+	}, nil
+}
+
+// searchKanji looks up kanji by meaning, reading or by the characters themselves
+func (s *Server) searchKanji(ctx context.Context, searchTerm string, options SearchOptions) (*SearchResult, error) {
+	searchTerm = strings.ToLower(searchTerm)
+	searchTermType := DetectSearchTermType(searchTerm)
+	db := s.dictionaryFor(ctx).db
+
+	result := &SearchResult{
+		Query: searchTerm,
+		Type:  searchTermType,
+		From:  options.from(),
+		Size:  options.Size,
+	}
+
+	searchRequest := bleve.NewSearchRequestOptions(kanjiQuery(searchTerm, searchTermType), result.Size, result.From, false)
+	searchRequest.SortBy([]string{"-_score", "_id"})
+
+	searchResults, err := db.KanjiIndex.SearchInContext(ctx, searchRequest)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to run Bleve query", "error", err)
+		return nil, fmt.Errorf("failed to search Bleve kanji index: %w", err)
+	}
+
+	result.Total = searchResults.Total
+	if len(searchResults.Hits) == 0 {
+		return result, ErrNoResults
+	}
+
+	literals := hitIDs(searchResults)
+	result.Kanji, err = fetchKanji(ctx, literals, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch kanji from store", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func kanjiQuery(searchTerm string, searchTermType SearchTermType) query.Query {
+	switch searchTermType {
+	case Kanji:
+		literalsQuery := bleve.NewDisjunctionQuery()
+		for _, literal := range kanjiIn(searchTerm) {
+			literalQuery := bleve.NewTermQuery(literal)
+			literalQuery.SetField("literal")
+			literalsQuery.AddQuery(literalQuery)
+		}
+		return literalsQuery
+	case Kana:
+		readingQuery := bleve.NewTermQuery(kana.ToHiraganaFromKatakana(searchTerm))
+		readingQuery.SetField("readings")
+		return readingQuery
+	default:
+		meaningsQuery := bleve.NewMatchQuery(searchTerm)
+		meaningsQuery.SetField("meanings")
+
+		mainQuery := bleve.NewDisjunctionQuery(meaningsQuery)
+		if hiragana, ok := kana.ToHiragana(searchTerm); ok {
+			readingQuery := bleve.NewTermQuery(hiragana)
+			readingQuery.SetField("readings")
+			mainQuery.AddQuery(readingQuery)
+		}
+		return mainQuery
+	}
+}
+
+// findKanji returns the kanji written in the query, in the same order
+func findKanji(ctx context.Context, searchTerm string, db *database.Database) ([]database.Kanji, error) {
+	literals := kanjiIn(searchTerm)
+	if len(literals) == 0 {
+		return nil, nil
+	}
+
+	return fetchKanji(ctx, literals, db)
+}
+
+// kanjiIn returns every distinct kanji of the text, in order of appearance
+func kanjiIn(text string) []string {
+	var literals []string
+	seen := make(map[rune]bool)
+
+	for _, r := range text {
+		if isKanji(r) && !seen[r] {
+			seen[r] = true
+			literals = append(literals, string(r))
+		}
+	}
+
+	return literals
+}
+
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// fetchKanji gets the kanji from the store, keeping the order of the literals
+func fetchKanji(ctx context.Context, literals []string, db *database.Database) ([]database.Kanji, error) {
+	kanji, err := db.Store.GetKanji(ctx, literals)
+	if err != nil {
+		return nil, err
+	}
+
+	byLiteral := make(map[string]database.Kanji, len(kanji))
+	for _, k := range kanji {
+		byLiteral[k.Literal] = k
+	}
+
+	sorted := make([]database.Kanji, 0, len(kanji))
+	for _, literal := range literals {
+		if k, ok := byLiteral[literal]; ok {
+			sorted = append(sorted, k)
+		}
+	}
+
+	return sorted, nil
+}
+
+// performContainsQuery returns the IDs of the words written with the kanji, common words first.
+// Compounds are indexed as bigrams in 'kanji_char', so any token containing the kanji is a match.
+func performContainsQuery(ctx context.Context, literal string, size int, db *database.Database) ([]string, uint64, error) {
+	containsQuery := bleve.NewWildcardQuery("*" + literal + "*")
+	containsQuery.SetField("kanji_char")
+
+	searchRequest := bleve.NewSearchRequestOptions(containsQuery, size, 0, false)
+	searchRequest.SortBy([]string{"-common", "_id"})
+
+	searchResults, err := db.BleveIndex.SearchInContext(ctx, searchRequest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search Bleve index: %w", err)
+	}
+
+	return hitIDs(searchResults), searchResults.Total, nil
+}
+
+// hitIDs returns the document ID of every hit, for requests that don't load any stored field
+func hitIDs(searchResults *bleve.SearchResult) []string {
+	ids := make([]string, 0, len(searchResults.Hits))
+	for _, hit := range searchResults.Hits {
+		ids = append(ids, hit.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/segment"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// Lookup searches the current release the way the server does, but opens it read-only and
+// without the cache, the metrics or the watcher switching releases. It's what 'tango lookup' uses.
+type Lookup struct {
+	db   *database.Database
+	tags map[string]string // Tag name to description
+	// forms splits texts to annotate, it's only read from the index the first time it's needed
+	forms func() (segment.Forms, error)
+}
+
+// OpenLookup opens the release being served
+func OpenLookup(ctx context.Context, config types.ServerConfig) (*Lookup, error) {
+	release, err := database.CurrentRelease(config.DataDir, config.JmdictVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.OpenDatabase(ctx, &config, release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	tags, err := fetchTags(db)
+	if err != nil {
+		db.Close(context.Background())
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	return &Lookup{
+		db:    db,
+		tags:  tags,
+		forms: sync.OnceValues(func() (segment.Forms, error) { return loadForms(db.BleveIndex) }),
+	}, nil
+}
+
+// Release is the release being searched
+func (l *Lookup) Release() database.Release {
+	return l.db.Release
+}
+
+// Search looks the words up, same results as the search page and /api/v1/search
+func (l *Lookup) Search(ctx context.Context, searchTerm string, options SearchOptions) (*SearchResult, error) {
+	return searchWords(ctx, normalizeSearchTerm(searchTerm), options, l.db)
+}
+
+// Annotate gives a reading to every word written with kanji, same spans as /api/v1/furigana
+func (l *Lookup) Annotate(ctx context.Context, text string, options FuriganaOptions) ([]furigana.Span, error) {
+	forms, err := l.forms()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load word forms: %w", err)
+	}
+
+	return annotate(ctx, text, options, l.db, forms)
+}
+
+// APIResponse is the result as /api/v1/search would answer it
+func (l *Lookup) APIResponse(result *SearchResult) APISearchResponse {
+	return newAPISearchResponse(result, l.TagDescriptions(result.Words...))
+}
+
+// TagDescriptions describes every tag referenced by the words
+func (l *Lookup) TagDescriptions(words ...database.Word) map[string]string {
+	return describeTags(l.tags, words...)
+}
+
+func (l *Lookup) Close(ctx context.Context) error {
+	return l.db.Close(ctx)
+}
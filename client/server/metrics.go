@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metrics are the Prometheus collectors of a server, exposed at /metrics
+type metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	bleveDuration   *prometheus.HistogramVec
+	storeDuration   *prometheus.HistogramVec
+	searches        *prometheus.CounterVec
+}
+
+func newMetrics(s *Server) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tango_http_requests_total",
+			Help: "HTTP requests by route and status code.",
+		}, []string{"route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tango_http_request_duration_seconds",
+			Help:    "Time spent answering HTTP requests, by route and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "status"}),
+		bleveDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tango_bleve_query_duration_seconds",
+			Help:    "Time spent running Bleve queries, by index.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"index"}),
+		storeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tango_store_duration_seconds",
+			Help:    "Time spent fetching documents from the store, by backend (mongo or bolt) and operation.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"backend", "operation"}),
+		searches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tango_searches_total",
+			Help: "Word searches by detected query type (romaji, kana or kanji) and whether they found any word.",
+		}, []string{"type", "results"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.bleveDuration,
+		m.storeDuration,
+		m.searches,
+	)
+	m.registerCache(s.cache)
+	m.registerRelease(s)
+
+	return m
+}
+
+func (m *metrics) registerCache(cache *searchCache) {
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "tango_search_cache_hits_total",
+			Help: "Word searches answered from the cache.",
+		}, func() float64 { return float64(cache.stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "tango_search_cache_misses_total",
+			Help: "Word searches that went to the dictionary.",
+		}, func() float64 { return float64(cache.stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "tango_search_cache_entries",
+			Help: "Word searches kept in the cache.",
+		}, func() float64 { return float64(cache.stats().Entries) }),
+	)
+}
+
+// registerRelease exposes the import stats of the release being served, as written in its manifest
+func (m *metrics) registerRelease(s *Server) {
+	entries := map[string]func(database.Manifest) int{
+		"words":     func(manifest database.Manifest) int { return manifest.Words },
+		"kanji":     func(manifest database.Manifest) int { return manifest.Kanji },
+		"radicals":  func(manifest database.Manifest) int { return manifest.Radicals },
+		"names":     func(manifest database.Manifest) int { return manifest.Names },
+		"sentences": func(manifest database.Manifest) int { return manifest.Sentences },
+		"tags":      func(manifest database.Manifest) int { return manifest.Tags },
+	}
+
+	for kind, count := range entries {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "tango_release_entries",
+			Help:        "Entries imported into the release being served, by kind.",
+			ConstLabels: prometheus.Labels{"kind": kind},
+		}, func() float64 { return float64(count(s.dictionary().manifest)) }))
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "tango_release_import_duration_seconds",
+			Help: "Time the import of the release being served took.",
+		}, func() float64 { return s.dictionary().manifest.Duration }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "tango_release_imported_timestamp_seconds",
+			Help: "When the release being served was imported.",
+		}, func() float64 { return float64(s.dictionary().manifest.ImportedAt.Unix()) }),
+	)
+}
+
+// handler serves the metrics in the Prometheus text format
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// observeRequest records a request, routes are the mux patterns so unknown paths don't add labels
+func (m *metrics) observeRequest(r *http.Request, statusCode int, duration time.Duration) {
+	route := r.Pattern
+	if route == "" {
+		route = "unmatched"
+	}
+
+	status := strconv.Itoa(statusCode)
+	m.requests.WithLabelValues(route, status).Inc()
+	m.requestDuration.WithLabelValues(route, status).Observe(duration.Seconds())
+}
+
+func (m *metrics) observeSearch(searchTermType SearchTermType, found bool) {
+	results := "none"
+	if found {
+		results = "some"
+	}
+
+	m.searches.WithLabelValues(string(searchTermType), results).Inc()
+}
+
+// instrument times the Bleve queries and store fetches of the database
+func (m *metrics) instrument(db *database.Database, backend string) {
+	db.Store = &instrumentedStore{Store: db.Store, backend: backend, metrics: m}
+	db.BleveIndex = &instrumentedIndex{bleveIndex: db.BleveIndex, name: "words", metrics: m}
+	db.KanjiIndex = &instrumentedIndex{bleveIndex: db.KanjiIndex, name: "kanji", metrics: m}
+	db.NamesIndex = &instrumentedIndex{bleveIndex: db.NamesIndex, name: "names", metrics: m}
+	db.SentencesIndex = &instrumentedIndex{bleveIndex: db.SentencesIndex, name: "sentences", metrics: m}
+}
+
+// bleveIndex names the embedded index, bleve.Index already has an Index method
+type bleveIndex = bleve.Index
+
+type instrumentedIndex struct {
+	bleveIndex
+	name    string
+	metrics *metrics
+}
+
+func (i *instrumentedIndex) Search(req *bleve.SearchRequest) (*bleve.SearchResult, error) {
+	return i.SearchInContext(context.Background(), req)
+}
+
+func (i *instrumentedIndex) SearchInContext(ctx context.Context, req *bleve.SearchRequest) (*bleve.SearchResult, error) {
+	defer i.metrics.observeQuery(i.name, time.Now())
+	return i.bleveIndex.SearchInContext(ctx, req)
+}
+
+func (m *metrics) observeQuery(index string, startTime time.Time) {
+	m.bleveDuration.WithLabelValues(index).Observe(time.Since(startTime).Seconds())
+}
+
+// instrumentedStore times the reads, writes only happen while importing
+type instrumentedStore struct {
+	database.Store
+	backend string
+	metrics *metrics
+}
+
+func (s *instrumentedStore) observe(operation string, startTime time.Time) {
+	s.metrics.storeDuration.WithLabelValues(s.backend, operation).Observe(time.Since(startTime).Seconds())
+}
+
+func (s *instrumentedStore) GetWords(ctx context.Context, ids []string) ([]database.Word, error) {
+	defer s.observe("get_words", time.Now())
+	return s.Store.GetWords(ctx, ids)
+}
+
+func (s *instrumentedStore) GetWord(ctx context.Context, id string) (*database.Word, error) {
+	defer s.observe("get_word", time.Now())
+	return s.Store.GetWord(ctx, id)
+}
+
+func (s *instrumentedStore) GetKanji(ctx context.Context, literals []string) ([]database.Kanji, error) {
+	defer s.observe("get_kanji", time.Now())
+	return s.Store.GetKanji(ctx, literals)
+}
+
+func (s *instrumentedStore) GetKanjiCharacter(ctx context.Context, literal string) (*database.Kanji, error) {
+	defer s.observe("get_kanji_character", time.Now())
+	return s.Store.GetKanjiCharacter(ctx, literal)
+}
+
+func (s *instrumentedStore) GetRadicals(ctx context.Context) ([]database.Radical, error) {
+	defer s.observe("get_radicals", time.Now())
+	return s.Store.GetRadicals(ctx)
+}
+
+func (s *instrumentedStore) GetNames(ctx context.Context, ids []string) ([]database.Name, error) {
+	defer s.observe("get_names", time.Now())
+	return s.Store.GetNames(ctx, ids)
+}
+
+func (s *instrumentedStore) GetSentences(ctx context.Context, ids []string) ([]database.Sentence, error) {
+	defer s.observe("get_sentences", time.Now())
+	return s.Store.GetSentences(ctx, ids)
+}
+
+func (s *instrumentedStore) GetTags(ctx context.Context) ([]database.Tag, error) {
+	defer s.observe("get_tags", time.Now())
+	return s.Store.GetTags(ctx)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func testMetrics() *metrics {
+	return newMetrics(&Server{cache: newSearchCache(10, time.Minute)})
+}
+
+func TestObserveRequest(t *testing.T) {
+	m := testMetrics()
+
+	r := httptest.NewRequest("GET", "/search?query=eat", nil)
+	r.Pattern = "GET /search"
+	m.observeRequest(r, 200, time.Millisecond)
+	m.observeRequest(r, 200, time.Millisecond)
+	m.observeRequest(httptest.NewRequest("GET", "/nowhere", nil), 404, time.Millisecond)
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET /search", "200")); got != 2 {
+		t.Errorf("requests of GET /search = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("unmatched", "404")); got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+}
+
+func TestObserveSearch(t *testing.T) {
+	m := testMetrics()
+
+	m.observeSearch(Kana, true)
+	m.observeSearch(Romaji, false)
+	m.observeSearch(Romaji, false)
+
+	if got := testutil.ToFloat64(m.searches.WithLabelValues("romaji", "none")); got != 2 {
+		t.Errorf("romaji searches without results = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.searches.WithLabelValues("kana", "some")); got != 1 {
+		t.Errorf("kana searches with results = %v, want 1", got)
+	}
+}
+
+type fakeStore struct {
+	database.Store
+}
+
+func (fakeStore) GetWord(ctx context.Context, id string) (*database.Word, error) {
+	return &database.Word{ID: id}, nil
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	m := testMetrics()
+	store := &instrumentedStore{Store: fakeStore{}, backend: "bolt", metrics: m}
+
+	if word, err := store.GetWord(context.Background(), "1"); err != nil || word.ID != "1" {
+		t.Fatalf("GetWord() = %v, %v", word, err)
+	}
+
+	if got := testutil.CollectAndCount(m.storeDuration, "tango_store_duration_seconds"); got != 1 {
+		t.Errorf("store duration series = %d, want 1", got)
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/izquiratops/tango/common/logging"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 64
+)
+
+// quietRoutes are only logged at debug level
+var quietRoutes = map[string]bool{
+	metricsRoute: true,
+	healthRoute:  true,
+	readyRoute:   true,
+}
+
+// middleware wraps a handler with something every request goes through
+type middleware func(http.Handler) http.Handler
+
+// chain wraps the handler with the middlewares, the first one sees the request first
+func chain(handler http.Handler, middlewares ...middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// requestID tags the request with the ID given by the proxy, or a new one. It's sent back in
+// the response and logged along every record of the request.
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	// IDs end up in the logs, so only plain characters are accepted
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	var id [8]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// statusRecorder remembers what the handler sent, for the access log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int // Zero until the header is written
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if r.status == 0 {
+		r.status = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// accessLog times every request, then logs it and records it in the metrics
+func (s *Server) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		duration := time.Since(startTime)
+		s.metrics.observeRequest(r, recorder.status, duration)
+
+		// Prometheus and the healthchecks poll every few seconds, those requests would bury the rest
+		level := slog.LevelInfo
+		if quietRoutes[r.Pattern] {
+			level = slog.LevelDebug
+		}
+
+		slog.LogAttrs(r.Context(), level, "Request served",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", r.Pattern),
+			slog.String("query", r.URL.Query().Get("query")),
+			slog.Int("status", recorder.status),
+			slog.Int("bytes", recorder.bytes),
+			slog.Duration("duration", duration),
+			slog.String("ip", r.RemoteAddr),
+		)
+	})
+}
+
+// recoverer turns a panicking handler into a 500 response instead of a dropped connection
+func recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			if value == http.ErrAbortHandler {
+				// Used on purpose to abort the response, net/http handles it
+				panic(value)
+			}
+
+			slog.ErrorContext(r.Context(), "Panic while serving request", "panic", value, "stack", string(debug.Stack()))
+
+			// Nothing can be done once the handler started writing
+			if recorder, ok := w.(*statusRecorder); !ok || recorder.status == 0 {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
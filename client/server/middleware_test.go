@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/izquiratops/tango/common/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"no header", "", false},
+		{"given by the proxy", "abc-123_x.y", true},
+		{"unsafe characters", "abc\n123", false},
+		{"too long", string(bytes.Repeat([]byte("a"), maxRequestIDLength+1)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				r.Header.Set(requestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			sent := w.Header().Get(requestIDHeader)
+			if sent == "" || sent != seen {
+				t.Fatalf("sent ID %q, handler saw %q", sent, seen)
+			}
+			if tt.keep && sent != tt.header {
+				t.Errorf("ID = %q, want %q", sent, tt.header)
+			}
+			if !tt.keep && sent == tt.header {
+				t.Errorf("ID %q should have been replaced", sent)
+			}
+		})
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	handler := recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(logging.New(&logs, logging.JSONFormat, slog.LevelInfo))
+	defer slog.SetDefault(defaultLogger)
+
+	s := &Server{cache: newSearchCache(10, time.Minute)}
+	s.metrics = newMetrics(s)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /words/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("missing"))
+	})
+	handler := chain(mux, requestID, s.accessLog, recoverer)
+
+	r := httptest.NewRequest("GET", "/words/42", nil)
+	r.Header.Set(requestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	var record struct {
+		Msg       string `json:"msg"`
+		Route     string `json:"route"`
+		Status    int    `json:"status"`
+		Bytes     int    `json:"bytes"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("access log isn't a JSON record: %v (%q)", err, logs.String())
+	}
+
+	if record.Msg != "Request served" || record.Route != "GET /words/{id}" || record.Status != http.StatusNotFound ||
+		record.Bytes != len("missing") || record.RequestID != "req-1" {
+		t.Errorf("unexpected access log record %+v", record)
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/izquiratops/tango/common/database"
+)
+
+// Tabs of the search page, names are listed apart so they don't push words down
+const (
+	WordsTab = "words"
+	NamesTab = "names"
+)
+
+// searchNames runs the query on the names index. It's a separate index, so name hits
+// never change how words are ranked.
+func (s *Server) searchNames(ctx context.Context, searchTerm string, options SearchOptions) (*SearchResult, error) {
+	searchTerm = strings.ToLower(searchTerm)
+	searchTermType := DetectSearchTermType(searchTerm)
+	db := s.dictionaryFor(ctx).db
+
+	result := &SearchResult{
+		Query: searchTerm,
+		Type:  searchTermType,
+		From:  options.from(),
+		Size:  options.Size,
+	}
+
+	ids, total, err := performBleveQuery(ctx, searchTerm, searchTermType, result.From, result.Size, db.NamesIndex)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to run Bleve query on names", "error", err)
+		return nil, err
+	}
+
+	result.Total = total
+	if len(ids) == 0 {
+		return result, ErrNoResults
+	}
+
+	names, err := fetchNamesByIDs(ctx, ids, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch names from store", "error", err)
+		return nil, err
+	}
+
+	result.Names = names
+	return result, nil
+}
+
+// countNames returns how many names match the query, shown on the names tab while browsing words
+func (s *Server) countNames(ctx context.Context, searchTerm string) (uint64, error) {
+	searchTerm = strings.ToLower(searchTerm)
+	_, total, err := performBleveQuery(ctx, searchTerm, DetectSearchTermType(searchTerm), 0, 0, s.dictionaryFor(ctx).db.NamesIndex)
+
+	return total, err
+}
+
+func fetchNamesByIDs(ctx context.Context, ids []string, db *database.Database) ([]database.Name, error) {
+	names, err := db.Store.GetNames(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the order given by Bleve
+	positions := make(map[string]int, len(ids))
+	for i, id := range ids {
+		positions[id] = i
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return positions[names[i].ID] < positions[names[j].ID]
+	})
+
+	return names, nil
+}
+
+// usedNameTags collects the description of every name type referenced by the given names
+func (s *Server) usedNameTags(ctx context.Context, names ...database.Name) map[string]string {
+	dict := s.dictionaryFor(ctx)
+	used := make(map[string]string)
+
+	for _, name := range names {
+		for _, nameType := range name.Types {
+			used[nameType] = dict.tagDescription(nameType)
+		}
+	}
+
+	return used
+}
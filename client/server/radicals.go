@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/utils"
+)
+
+const maxPickerKanji = 500 // Kanji listed at once by the radical picker, a single radical may match thousands
+
+// RadicalOption is a radical of the picker, along with the state it's shown in
+type RadicalOption struct {
+	database.Radical
+	Selected bool
+	Valid    bool   // Some kanji has it along with every selected radical
+	Toggle   string // Value of the 'radicals' parameter after clicking it
+}
+
+type KanjiGroup struct {
+	StrokeCount int      `json:"strokeCount"`
+	Kanji       []string `json:"kanji"`
+}
+
+type RadicalPick struct {
+	Selected []string
+	Radicals []RadicalOption
+	Groups   []KanjiGroup // Matching kanji grouped by stroke count, empty until something is selected
+	Total    uint64       // Matching kanji, not only the ones listed
+}
+
+// parseRadicals reads the comma separated radicals parameter, dropping duplicates and unknown radicals
+func parseRadicals(values url.Values, radicals []database.Radical) []string {
+	known := make(map[string]bool, len(radicals))
+	for _, radical := range radicals {
+		known[radical.Literal] = true
+	}
+
+	var selected []string
+	for _, literal := range strings.Split(values.Get("radicals"), ",") {
+		literal = strings.TrimSpace(literal)
+		if known[literal] && !utils.ContainsString(selected, literal) {
+			selected = append(selected, literal)
+		}
+	}
+
+	return selected
+}
+
+// pickRadicals finds the kanji made of every selected radical, and the radicals that can still be
+// added to the selection without leaving it empty
+func (s *Server) pickRadicals(ctx context.Context, selected []string) (*RadicalPick, error) {
+	dict := s.dictionaryFor(ctx)
+	pick := &RadicalPick{
+		Selected: selected,
+	}
+
+	// Without a selection every radical is valid
+	valid := make(map[string]bool, len(dict.radicals))
+	for _, radical := range dict.radicals {
+		valid[radical.Literal] = len(selected) == 0
+	}
+
+	if len(selected) > 0 {
+		componentsQuery := bleve.NewConjunctionQuery()
+		for _, literal := range selected {
+			componentQuery := bleve.NewTermQuery(literal)
+			componentQuery.SetField("components")
+			componentsQuery.AddQuery(componentQuery)
+		}
+
+		searchRequest := bleve.NewSearchRequestOptions(componentsQuery, maxPickerKanji, 0, false)
+		searchRequest.SortBy([]string{"stroke_count", "_id"})
+		searchRequest.Fields = []string{"stroke_count"}
+		// Every component of the matching kanji, that's the radicals that remain valid
+		searchRequest.AddFacet("components", bleve.NewFacetRequest("components", len(dict.radicals)))
+
+		searchResults, err := dict.db.KanjiIndex.SearchInContext(ctx, searchRequest)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to run Bleve query on radicals", "error", err)
+			return nil, fmt.Errorf("failed to search Bleve kanji index: %w", err)
+		}
+
+		pick.Total = searchResults.Total
+		pick.Groups = groupByStrokeCount(searchResults)
+
+		if facet, ok := searchResults.Facets["components"]; ok && facet.Terms != nil {
+			for _, term := range facet.Terms.Terms() {
+				valid[term.Term] = true
+			}
+		}
+	}
+
+	for _, radical := range dict.radicals {
+		option := RadicalOption{
+			Radical:  radical,
+			Selected: utils.ContainsString(selected, radical.Literal),
+			Valid:    valid[radical.Literal],
+		}
+		option.Toggle = toggleRadical(selected, radical.Literal)
+
+		pick.Radicals = append(pick.Radicals, option)
+	}
+
+	return pick, nil
+}
+
+// groupByStrokeCount splits hits sorted by stroke count into one group per count
+func groupByStrokeCount(searchResults *bleve.SearchResult) []KanjiGroup {
+	var groups []KanjiGroup
+
+	for _, hit := range searchResults.Hits {
+		strokeCount := 0
+		if value, ok := hit.Fields["stroke_count"].(float64); ok {
+			strokeCount = int(value)
+		}
+
+		if len(groups) == 0 || groups[len(groups)-1].StrokeCount != strokeCount {
+			groups = append(groups, KanjiGroup{StrokeCount: strokeCount})
+		}
+
+		last := &groups[len(groups)-1]
+		last.Kanji = append(last.Kanji, hit.ID)
+	}
+
+	return groups
+}
+
+// toggleRadical returns the selection after adding or removing the radical, as the comma separated parameter
+func toggleRadical(selected []string, literal string) string {
+	toggled := make([]string, 0, len(selected)+1)
+	found := false
+
+	for _, s := range selected {
+		if s == literal {
+			found = true
+			continue
+		}
+		toggled = append(toggled, s)
+	}
+
+	if !found {
+		toggled = append(toggled, literal)
+	}
+
+	return strings.Join(toggled, ",")
+}
+
+// sortRadicals orders radicals the way RADKFILE lists them, by stroke count
+func sortRadicals(radicals []database.Radical) []database.Radical {
+	sort.SliceStable(radicals, func(i, j int) bool {
+		if radicals[i].StrokeCount != radicals[j].StrokeCount {
+			return radicals[i].StrokeCount < radicals[j].StrokeCount
+		}
+		return radicals[i].Literal < radicals[j].Literal
+	})
+
+	return radicals
+}
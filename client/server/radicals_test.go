@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+)
+
+func TestParseRadicals(t *testing.T) {
+	radicals := []database.Radical{
+		{Literal: "一", StrokeCount: 1},
+		{Literal: "口", StrokeCount: 3},
+		{Literal: "木", StrokeCount: 4},
+	}
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"", nil},
+		{"radicals=口,木", []string{"口", "木"}},
+		{"radicals=木, 口 ,木", []string{"木", "口"}},
+		{"radicals=口,犬,", []string{"口"}},
+	}
+
+	for _, tc := range testCases {
+		values, _ := url.ParseQuery(tc.query)
+		if got := parseRadicals(values, radicals); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("parseRadicals(%q) = %v, want %v", tc.query, got, tc.expected)
+		}
+	}
+}
+
+func TestToggleRadical(t *testing.T) {
+	testCases := []struct {
+		selected []string
+		literal  string
+		expected string
+	}{
+		{nil, "口", "口"},
+		{[]string{"口"}, "木", "口,木"},
+		{[]string{"口", "木"}, "口", "木"},
+		{[]string{"口"}, "口", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := toggleRadical(tc.selected, tc.literal); got != tc.expected {
+			t.Errorf("toggleRadical(%v, %q) = %q, want %q", tc.selected, tc.literal, got, tc.expected)
+		}
+	}
+}
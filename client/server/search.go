@@ -5,53 +5,201 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/izquiratops/tango/common/database"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/izquiratops/tango/common/kana"
+	"github.com/izquiratops/tango/common/types"
 )
 
 const (
-	defaultSearchSize = 20
-	defaultSearchFrom = 0
+	defaultSearchPage = 1
+	// Deep paging makes Bleve collect every previous hit, so pages beyond this window are refused
+	maxSearchWindow = 10000
 )
 
-func (s *Server) search(searchTerm string) ([]database.Word, error) {
-	// Make sure to search using lowercase only
-	searchTerm = strings.ToLower(searchTerm)
+var (
+	// ErrNoResults is returned by search when the query doesn't match any word
+	ErrNoResults = errors.New("no results found")
+	// ErrWordNotFound is returned when looking up a word ID that doesn't exist
+	ErrWordNotFound = errors.New("word not found")
+)
+
+type SearchOptions struct {
+	Page int // 1-based page number
+	Size int // Results per page
+}
+
+// parseSearchOptions reads 'page' and 'size' from the query string, falling back
+// to the defaults for missing or invalid values and clamping them to sane caps
+func parseSearchOptions(values url.Values, limits types.SearchConfig) SearchOptions {
+	options := SearchOptions{
+		Page: defaultSearchPage,
+		Size: limits.DefaultSize,
+	}
+
+	if size, err := strconv.Atoi(values.Get("size")); err == nil && size > 0 {
+		options.Size = min(size, limits.MaxSize)
+	}
+
+	if page, err := strconv.Atoi(values.Get("page")); err == nil && page > 0 {
+		maxPage := maxSearchWindow / options.Size
+		options.Page = min(page, maxPage)
+	}
+
+	return options
+}
+
+func (o SearchOptions) from() int {
+	return (o.Page - 1) * o.Size
+}
+
+type SearchResult struct {
+	Query string
+	Type  SearchTermType
+	Total uint64 // Total hits reported by Bleve, not only the ones in Words
+	From  int
+	Size  int
+	Words []database.Word
+	// Dictionary forms the query is a conjugation of, only looked up on the first page
+	Inflections []Inflection
+	// Kanji written in the query, only looked up on the first page
+	Kanji []database.Kanji
+	// Proper names, only filled by searchNames
+	Names []database.Name
+	// Example sentences of every word, by word ID
+	Examples map[string][]database.Sentence
+	// Tatoeba sentences, only filled by searchSentences
+	Sentences []database.Sentence
+}
+
+// Search looks the words up in the dictionary being served, popular queries are answered from the cache
+func (s *Server) Search(ctx context.Context, searchTerm string, options SearchOptions) (*SearchResult, error) {
+	dict := s.dictionaryFor(ctx)
+	// The cache key and the search must see the same term, or differently spaced queries would share results
+	searchTerm = normalizeSearchTerm(searchTerm)
+	key := searchCacheKey(searchTerm, options)
+
+	result, err := s.cache.get(dict.version(), key, func() (*SearchResult, error) {
+		// Requests waiting on the same search share this one, so it's not cancelled if the first one goes away
+		return searchWords(context.WithoutCancel(ctx), searchTerm, options, dict.db)
+	})
+	if err == nil || errors.Is(err, ErrNoResults) {
+		s.metrics.observeSearch(DetectSearchTermType(searchTerm), err == nil && len(result.Words) > 0)
+	}
+
+	return result, err
+}
+
+// normalizeSearchTerm lowercases the term and collapses its spaces, searches only ever see normalized terms
+func normalizeSearchTerm(searchTerm string) string {
+	return strings.Join(strings.Fields(strings.ToLower(searchTerm)), " ")
+}
 
-	ids, err := performBleveQuery(searchTerm, s.db)
+// searchWords runs a search of a term already normalized by normalizeSearchTerm
+func searchWords(ctx context.Context, searchTerm string, options SearchOptions, db *database.Database) (*SearchResult, error) {
+	searchTermType := DetectSearchTermType(searchTerm)
+
+	result := &SearchResult{
+		Query: searchTerm,
+		Type:  searchTermType,
+		From:  options.from(),
+		Size:  options.Size,
+	}
+
+	// Conjugated queries (食べました, 高くて) are looked up by their dictionary forms first
+	if searchTermType != Romaji && result.From == 0 {
+		inflections, err := findInflections(ctx, searchTerm, db)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to deinflect query", "error", err)
+			return nil, err
+		}
+
+		result.Inflections = inflections
+	}
+
+	if searchTermType == Kanji && result.From == 0 {
+		kanji, err := findKanji(ctx, searchTerm, db)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to fetch kanji from store", "error", err)
+			return nil, err
+		}
+
+		result.Kanji = kanji
+	}
+
+	ids, total, err := performBleveQuery(ctx, searchTerm, searchTermType, result.From, result.Size, db.BleveIndex)
 	if err != nil {
-		log.Printf("Failed to run Bleve query: %v", err)
+		slog.ErrorContext(ctx, "Failed to run Bleve query", "error", err)
 		return nil, err
 	}
 
+	result.Total = total
 	if len(ids) == 0 {
-		emptyResultsErr := errors.New("EMPTY_LIST")
-		return nil, emptyResultsErr
+		if len(result.Inflections) == 0 {
+			if len(result.Kanji) > 0 {
+				// Kanji only used in names or rare compounds still have their cards
+				return result, nil
+			}
+			return result, ErrNoResults
+		}
+
+		// Nothing matches the conjugated form itself, show the dictionary forms instead
+		for _, inflection := range result.Inflections {
+			result.Words = append(result.Words, inflection.Word)
+		}
+		result.Total = uint64(len(result.Words))
+
+		return result, addExamples(ctx, result, db)
+	}
+
+	words, err := fetchWordsByIDs(ctx, ids, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch words from store", "error", err)
+		return nil, err
 	}
 
-	results, err := fetchWordsByIDs(ids, s.db)
+	result.Words = words
+	return result, addExamples(ctx, result, db)
+}
+
+func addExamples(ctx context.Context, result *SearchResult, db *database.Database) error {
+	examples, err := findExamples(ctx, result.Words, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch example sentences", "error", err)
+		return err
+	}
+
+	result.Examples = examples
+	return nil
+}
+
+func (s *Server) lookupWord(ctx context.Context, id string) (*database.Word, error) {
+	word, err := fetchWordByID(ctx, id, s.dictionaryFor(ctx).db)
 	if err != nil {
-		log.Printf("Failed to run MongoDB find: %v", err)
+		if !errors.Is(err, ErrWordNotFound) {
+			slog.ErrorContext(ctx, "Failed to fetch words from store", "error", err)
+		}
 		return nil, err
 	}
 
-	return results, nil
+	return word, nil
 }
 
 // Code related to Bleve
-func performBleveQuery(searchTerm string, db *database.Database) ([]string, error) {
-	mainQuery := bleve.NewBooleanQuery()
 
-	searchTermType := DetectSearchTermType(searchTerm)
+// performBleveQuery runs a search on the words index, or on any index sharing its mapping like the names one
+func performBleveQuery(ctx context.Context, searchTerm string, searchTermType SearchTermType, from int, size int, index bleve.Index) ([]string, uint64, error) {
+	mainQuery := bleve.NewBooleanQuery()
 
 	switch searchTermType {
-	case "romaji":
+	case Romaji:
 		meaningsPhraseQuery := bleve.NewMatchQuery(searchTerm)
 		meaningsPhraseQuery.SetField("meanings")
 		meaningsPhraseQuery.SetBoost(4.0)
@@ -65,7 +213,12 @@ func performBleveQuery(searchTerm string, db *database.Database) ([]string, erro
 			meaningsPhraseQuery,
 			meaningsFuzzyQuery,
 		)
-	case "kana":
+
+		// Latin text may also be romaji, so search the readings too and let scoring merge both
+		if hiragana, ok := kana.ToHiragana(searchTerm); ok {
+			mainQuery.AddShould(romajiQueries(hiragana)...)
+		}
+	case Kana:
 		kanaExactQuery := bleve.NewMatchQuery(searchTerm)
 		kanaExactQuery.SetField("kana_exact")
 		kanaExactQuery.SetBoost(5.0)
@@ -83,7 +236,7 @@ func performBleveQuery(searchTerm string, db *database.Database) ([]string, erro
 			kanaPrefixQuery,
 			kanaCharQuery,
 		)
-	case "kanji":
+	case Kanji:
 		kanjiExactQuery := bleve.NewMatchPhraseQuery(searchTerm)
 		kanjiExactQuery.SetField("kanji_exact")
 		kanjiExactQuery.SetBoost(5.0)
@@ -105,26 +258,82 @@ func performBleveQuery(searchTerm string, db *database.Database) ([]string, erro
 
 	searchRequest := bleve.NewSearchRequest(mainQuery)
 	searchRequest.Explain = true // Debug
-	searchRequest.Size = defaultSearchSize
-	searchRequest.From = defaultSearchFrom
+	searchRequest.Size = size
+	searchRequest.From = from
+	// Break score ties by ID, otherwise hits with equal scores may jump between pages
+	searchRequest.SortBy([]string{"-_score", "_id"})
 	searchRequest.Fields = []string{
 		"id",
 		"kana_char",
 		"kanji_char",
 		"meanings",
+		"romaji",
 	}
 
-	searchResults, err := db.BleveIndex.Search(searchRequest)
+	searchResults, err := index.SearchInContext(ctx, searchRequest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search Bleve index: %w", err)
+		return nil, 0, fmt.Errorf("failed to search Bleve index: %w", err)
+	}
+
+	ids := extractBleveResult(ctx, searchResults)
+
+	return ids, searchResults.Total, nil
+}
+
+func romajiQueries(hiragana string) []query.Query {
+	romajiExactQuery := bleve.NewTermQuery(kana.ToRomaji(hiragana))
+	romajiExactQuery.SetField("romaji")
+	romajiExactQuery.SetBoost(3.0)
+
+	romajiPrefixQuery := bleve.NewPrefixQuery(kana.ToRomaji(hiragana))
+	romajiPrefixQuery.SetField("romaji")
+	romajiPrefixQuery.SetBoost(1.0)
+
+	hiraganaExactQuery := bleve.NewTermQuery(hiragana)
+	hiraganaExactQuery.SetField("kana_exact")
+	hiraganaExactQuery.SetBoost(3.0)
+
+	// Loanwords are written in katakana
+	katakanaExactQuery := bleve.NewTermQuery(kana.ToKatakana(hiragana))
+	katakanaExactQuery.SetField("kana_exact")
+	katakanaExactQuery.SetBoost(3.0)
+
+	return []query.Query{
+		romajiExactQuery,
+		romajiPrefixQuery,
+		hiraganaExactQuery,
+		katakanaExactQuery,
+	}
+}
+
+// performExactQuery returns the IDs of the words written exactly as any of the forms, in kanji or kana
+func performExactQuery(ctx context.Context, forms []string, size int, db *database.Database) ([]string, error) {
+	exactQuery := bleve.NewDisjunctionQuery()
+
+	for _, form := range forms {
+		kanjiExactQuery := bleve.NewTermQuery(form)
+		kanjiExactQuery.SetField("kanji_exact")
+
+		kanaExactQuery := bleve.NewTermQuery(form)
+		kanaExactQuery.SetField("kana_exact")
+
+		exactQuery.AddQuery(kanjiExactQuery, kanaExactQuery)
 	}
 
-	ids := extractBleveResult(searchResults)
+	searchRequest := bleve.NewSearchRequest(exactQuery)
+	searchRequest.Size = size
+	searchRequest.SortBy([]string{"-_score", "_id"})
+	searchRequest.Fields = []string{"id"}
+
+	searchResults, err := db.BleveIndex.SearchInContext(ctx, searchRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Bleve index: %w", err)
+	}
 
-	return ids, nil
+	return extractBleveResult(ctx, searchResults), nil
 }
 
-func extractBleveResult(searchResults *bleve.SearchResult) []string {
+func extractBleveResult(ctx context.Context, searchResults *bleve.SearchResult) []string {
 	var ids []string // List of Ids for every query hit
 
 	for _, hit := range searchResults.Hits {
@@ -133,13 +342,13 @@ func extractBleveResult(searchResults *bleve.SearchResult) []string {
 		// Serialize the map to a JSON byte slice
 		jsonBytes, err := json.Marshal(hit.Fields)
 		if err != nil {
-			fmt.Println("error marshalling fields:", err)
+			slog.ErrorContext(ctx, "Failed to marshal Bleve fields", "error", err)
 			continue
 		}
 
 		// Unmarshal the JSON byte slice into the BleveEntry struct using a custom unmarshaler
 		if err := json.Unmarshal(jsonBytes, &entry); err != nil {
-			fmt.Println("error unmarshalling entry:", err)
+			slog.ErrorContext(ctx, "Failed to unmarshal Bleve entry", "error", err)
 			continue
 		}
 
@@ -149,62 +358,36 @@ func extractBleveResult(searchResults *bleve.SearchResult) []string {
 	return ids
 }
 
-// Code related to MongoDB
-func fetchWordsByIDs(ids []string, db *database.Database) ([]database.Word, error) {
-	// TODO: use ctx from request
-	ctx := context.Background()
-
-	filter := bson.M{
-		"_id": bson.M{
-			"$in": ids,
-		},
-	}
-
-	cursor, err := db.MongoWords.Find(ctx, filter)
+// Code related to the word store
+func fetchWordsByIDs(ctx context.Context, ids []string, db *database.Database) ([]database.Word, error) {
+	results, err := db.Store.GetWords(ctx, ids)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find documents in MongoDB: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	results, err := extractCursorResult(cursor, ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to iterate over cursor: %w", err)
+		return nil, err
 	}
 
-	// Sorting what mongo returns to match the order of Bleve IDs
+	// Sorting what the store returns to match the order of Bleve IDs
 	sortedResults := sortWords(results, ids)
 
 	return sortedResults, nil
 }
 
-func extractCursorResult(cursor *mongo.Cursor, ctx context.Context) ([]database.Word, error) {
-	var results []database.Word
-	for cursor.Next(ctx) {
-		var result database.Word
-		if err := cursor.Decode(&result); err != nil {
-			return nil, fmt.Errorf("failed to decode document: %w", err)
-		}
-		results = append(results, result)
-	}
-
-	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error: %w", err)
+func fetchWordByID(ctx context.Context, id string, db *database.Database) (*database.Word, error) {
+	word, err := db.Store.GetWord(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrWordNotFound
 	}
 
-	return results, nil
+	return word, err
 }
 
 func sortWords(results []database.Word, targetOrder []string) []database.Word {
+	positions := make(map[string]int, len(targetOrder))
+	for i, id := range targetOrder {
+		positions[id] = i
+	}
+
 	sort.SliceStable(results, func(i, j int) bool {
-		for _, id := range targetOrder {
-			if results[i].ID == id {
-				return true
-			}
-			if results[j].ID == id {
-				return false
-			}
-		}
-		return false // This should never be reached if all IDs are found
+		return positions[results[i].ID] < positions[results[j].ID]
 	})
 
 	return results
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+)
+
+func TestParseSearchOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected SearchOptions
+	}{
+		{"Defaults", "", SearchOptions{Page: 1, Size: 20}},
+		{"Explicit values", "page=3&size=50", SearchOptions{Page: 3, Size: 50}},
+		{"Invalid values", "page=-1&size=abc", SearchOptions{Page: 1, Size: 20}},
+		{"Size is capped", "size=1000", SearchOptions{Page: 1, Size: 100}},
+		{"Page is capped to the search window", "page=99999&size=100", SearchOptions{Page: 100, Size: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			got := parseSearchOptions(values, types.SearchConfig{DefaultSize: 20, MaxSize: 100})
+			if got != tt.expected {
+				t.Errorf("parseSearchOptions(%q) = %+v, want %+v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortWords(t *testing.T) {
+	words := []database.Word{{ID: "3"}, {ID: "1"}, {ID: "2"}}
+	got := sortWords(words, []string{"2", "3", "1"})
+
+	var ids []string
+	for _, w := range got {
+		ids = append(ids, w.ID)
+	}
+
+	if expected := []string{"2", "3", "1"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("sortWords() = %v, want %v", ids, expected)
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/izquiratops/tango/common/database"
+)
+
+const maxExamples = 3 // Example sentences listed under every word of the results
+
+// searchSentences looks up Tatoeba sentences, in English for latin queries and in Japanese otherwise
+func (s *Server) searchSentences(ctx context.Context, searchTerm string, options SearchOptions) (*SearchResult, error) {
+	searchTerm = strings.ToLower(searchTerm)
+	searchTermType := DetectSearchTermType(searchTerm)
+	db := s.dictionaryFor(ctx).db
+
+	result := &SearchResult{
+		Query: searchTerm,
+		Type:  searchTermType,
+		From:  options.from(),
+		Size:  options.Size,
+	}
+
+	searchRequest := bleve.NewSearchRequestOptions(sentenceQuery(searchTerm, searchTermType), result.Size, result.From, false)
+	searchRequest.SortBy([]string{"-_score", "_id"})
+
+	searchResults, err := db.SentencesIndex.SearchInContext(ctx, searchRequest)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to run Bleve query on sentences", "error", err)
+		return nil, fmt.Errorf("failed to search Bleve sentences index: %w", err)
+	}
+
+	result.Total = searchResults.Total
+	if len(searchResults.Hits) == 0 {
+		return result, ErrNoResults
+	}
+
+	ids := hitIDs(searchResults)
+	sentences, err := fetchSentencesByIDs(ctx, ids, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch sentences from store", "error", err)
+		return nil, err
+	}
+
+	result.Sentences = sentences
+	return result, nil
+}
+
+func sentenceQuery(searchTerm string, searchTermType SearchTermType) query.Query {
+	if searchTermType == Romaji {
+		// Every word must be in the translation, otherwise 'eat bread' lists every sentence with 'eat'
+		englishQuery := bleve.NewMatchQuery(searchTerm)
+		englishQuery.SetField("english")
+		englishQuery.SetOperator(query.MatchQueryOperatorAnd)
+
+		return englishQuery
+	}
+
+	// Japanese is indexed as bigrams, a lone character only shows up inside them
+	if utf8.RuneCountInString(searchTerm) == 1 {
+		containsQuery := bleve.NewWildcardQuery("*" + searchTerm + "*")
+		containsQuery.SetField("japanese")
+
+		return containsQuery
+	}
+
+	japaneseQuery := bleve.NewMatchPhraseQuery(searchTerm)
+	japaneseQuery.SetField("japanese")
+
+	return japaneseQuery
+}
+
+// findExamples returns a few example sentences of every word, by word ID
+func findExamples(ctx context.Context, words []database.Word, db *database.Database) (map[string][]database.Sentence, error) {
+	examples := make(map[string][]string, len(words))
+	var ids []string
+
+	for _, word := range words {
+		wordQuery := bleve.NewTermQuery(word.ID)
+		wordQuery.SetField("words")
+
+		// Shorter sentences score higher, they make better examples
+		searchRequest := bleve.NewSearchRequestOptions(wordQuery, maxExamples, 0, false)
+		searchRequest.SortBy([]string{"-_score", "_id"})
+
+		searchResults, err := db.SentencesIndex.SearchInContext(ctx, searchRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search Bleve sentences index: %w", err)
+		}
+
+		examples[word.ID] = hitIDs(searchResults)
+		ids = append(ids, examples[word.ID]...)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	sentences, err := db.Store.GetSentences(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]database.Sentence, len(sentences))
+	for _, sentence := range sentences {
+		byID[sentence.ID] = sentence
+	}
+
+	result := make(map[string][]database.Sentence, len(examples))
+	for wordID, sentenceIDs := range examples {
+		for _, id := range sentenceIDs {
+			if sentence, ok := byID[id]; ok {
+				result[wordID] = append(result[wordID], sentence)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func fetchSentencesByIDs(ctx context.Context, ids []string, db *database.Database) ([]database.Sentence, error) {
+	sentences, err := db.Store.GetSentences(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the order given by Bleve
+	positions := make(map[string]int, len(ids))
+	for i, id := range ids {
+		positions[id] = i
+	}
+
+	sort.SliceStable(sentences, func(i, j int) bool {
+		return positions[sentences[i].ID] < positions[sentences[j].ID]
+	})
+
+	return sentences, nil
+}
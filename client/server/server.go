@@ -1,13 +1,19 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
+	"sync"
+	"sync/atomic"
 
 	"github.com/izquiratops/tango/common/database"
 	"github.com/izquiratops/tango/common/types"
@@ -15,82 +21,215 @@ import (
 )
 
 type Server struct {
-	db           *database.Database
+	dict         atomic.Pointer[dictionary] // Release being served, replaced when a new one is promoted
+	cache        *searchCache               // Latest word searches of the release being served
+	metrics      *metrics
 	config       types.ServerConfig
 	staticPrefix http.Handler
+
+	stop      chan struct{}  // Closed by Close, stops the release watcher
+	watching  sync.WaitGroup // The release watcher
+	retiredMu sync.Mutex
+	retired   map[*dictionary]struct{} // Replaced releases still held by requests
 }
 
 type SearchData struct {
-	Query   string
-	Results []database.Word
+	Query       string
+	Tab         string // WordsTab or NamesTab
+	Results     []database.Word
+	Examples    map[string][]database.Sentence // Example sentences of the results, by word ID
+	Names       []database.Name
+	Inflections []Inflection
+	Kanji       []database.Kanji
+	Total       uint64
+	NamesTotal  uint64 // Names matching the query, counted while browsing the words tab
+	Pager       Pager
 }
 
-func (s *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+type Pager struct {
+	Page       int
+	Size       int
+	TotalPages int
+	PrevPage   int // Zero when there's no previous page
+	NextPage   int // Zero when there's no next page
+}
+
+func newPager(result *SearchResult) Pager {
+	page := result.From/result.Size + 1
+	totalPages := int((result.Total + uint64(result.Size) - 1) / uint64(result.Size))
+	// Pages beyond the search window can't be requested
+	totalPages = min(totalPages, maxSearchWindow/result.Size)
+
+	pager := Pager{
+		Page:       page,
+		Size:       result.Size,
+		TotalPages: totalPages,
+	}
+	if page > 1 {
+		pager.PrevPage = page - 1
+	}
+	if page < totalPages {
+		pager.NextPage = page + 1
+	}
+
+	return pager
+}
 
+func (s *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
 	templatePath, _ := utils.GetAbsolutePath("template/index.html")
 	http.ServeFile(w, r, templatePath)
-
-	duration := time.Since(startTime)
-	s.logRequest(r, http.StatusOK, duration)
 }
 
 func (s *Server) searchHandler(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
-	statusCode := http.StatusOK
-
 	query := r.URL.Query().Get("query")
-	results, err := s.search(query)
 
-	var templatePath string
+	// Sentences typed in the search box are split into words, unless a tab or page was picked on purpose
+	if !r.URL.Query().Has("tab") && !r.URL.Query().Has("page") {
+		sentence, err := looksLikeSentence(r.Context(), s.dictionaryFor(r.Context()), query)
+		if err != nil {
+			// The word search is still worth trying
+			slog.ErrorContext(r.Context(), "Failed to tell if the query is a sentence", "error", err)
+		}
+		if sentence {
+			http.Redirect(w, r, "/analyze?text="+url.QueryEscape(strings.TrimSpace(query)), http.StatusFound)
+			return
+		}
+	}
+
+	options := parseSearchOptions(r.URL.Query(), s.config.Search)
+	data := SearchData{
+		Query: query,
+		Tab:   WordsTab,
+	}
+
+	var result *SearchResult
+	var err error
+	if r.URL.Query().Get("tab") == NamesTab {
+		data.Tab = NamesTab
+		result, err = s.searchNames(r.Context(), query, options)
+	} else {
+		result, err = s.Search(r.Context(), query, options)
+
+		if err == nil || errors.Is(err, ErrNoResults) {
+			// Names are secondary, failing to count them doesn't fail the search
+			namesTotal, countErr := s.countNames(r.Context(), query)
+			if countErr != nil {
+				slog.ErrorContext(r.Context(), "Failed to count names", "error", countErr)
+			}
+			data.NamesTotal = namesTotal
+
+			if errors.Is(err, ErrNoResults) && namesTotal > 0 {
+				// Show the tabs anyway, the query is a name
+				err = nil
+			}
+		}
+	}
+
+	var templateName string
 	if err != nil {
-		if err.Error() == "EMPTY_LIST" {
-			templatePath, _ = utils.GetAbsolutePath("template/not_found.html")
+		if errors.Is(err, ErrNoResults) {
+			templateName = "not_found.html"
 		} else {
-			statusCode = http.StatusInternalServerError
-			http.Error(w, fmt.Sprintf("Search error: %v", err), statusCode)
-
-			duration := time.Since(startTime)
-			s.logRequest(r, statusCode, duration)
+			http.Error(w, fmt.Sprintf("Search error: %v", err), http.StatusInternalServerError)
 			return
 		}
 	} else {
-		templatePath, _ = utils.GetAbsolutePath("template/results.html")
+		templateName = "results.html"
 	}
 
-	// Parse template
-	tmpl, err := template.ParseFiles(templatePath)
+	if result != nil {
+		data.Results = result.Words
+		data.Examples = result.Examples
+		data.Names = result.Names
+		data.Inflections = result.Inflections
+		data.Kanji = result.Kanji
+		data.Total = result.Total
+		data.Pager = newPager(result)
+	}
+
+	s.renderTemplate(w, r, http.StatusOK, templateName, data)
+}
+
+func (s *Server) wordHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	word, err := s.lookupWord(r.Context(), id)
 	if err != nil {
-		statusCode = http.StatusInternalServerError
-		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), statusCode)
+		if errors.Is(err, ErrWordNotFound) {
+			s.renderTemplate(w, r, http.StatusNotFound, "not_found.html", SearchData{Query: id})
+		} else {
+			http.Error(w, fmt.Sprintf("Lookup error: %v", err), http.StatusInternalServerError)
+		}
+	} else {
+		s.renderTemplate(w, r, http.StatusOK, "word.html", word)
+	}
+}
 
-		duration := time.Since(startTime)
-		s.logRequest(r, statusCode, duration)
-		return
+func (s *Server) kanjiHandler(w http.ResponseWriter, r *http.Request) {
+	literal := r.PathValue("char")
+	data, err := s.lookupKanji(r.Context(), literal)
+	if err != nil {
+		if errors.Is(err, ErrKanjiNotFound) {
+			s.renderTemplate(w, r, http.StatusNotFound, "not_found.html", SearchData{Query: literal})
+		} else {
+			http.Error(w, fmt.Sprintf("Lookup error: %v", err), http.StatusInternalServerError)
+		}
+	} else {
+		s.renderTemplate(w, r, http.StatusOK, "kanji.html", data)
 	}
+}
 
-	// Render template
-	data := SearchData{
-		Query:   query,
-		Results: results,
+func (s *Server) analyzeHandler(w http.ResponseWriter, r *http.Request) {
+	text := strings.TrimSpace(r.URL.Query().Get("text"))
+	analysis, err := s.analyze(r.Context(), text)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Analyze error: %v", err), http.StatusInternalServerError)
+	} else {
+		s.renderTemplate(w, r, http.StatusOK, "analyze.html", analysis)
 	}
-	if err := tmpl.Execute(w, data); err != nil {
-		statusCode = http.StatusInternalServerError
-		http.Error(w, fmt.Sprintf("Template rendering error: %v", err), statusCode)
+}
 
-		duration := time.Since(startTime)
-		s.logRequest(r, statusCode, duration)
+func (s *Server) radicalsHandler(w http.ResponseWriter, r *http.Request) {
+	selected := parseRadicals(r.URL.Query(), s.dictionaryFor(r.Context()).radicals)
+	pick, err := s.pickRadicals(r.Context(), selected)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Radical lookup error: %v", err), http.StatusInternalServerError)
+	} else {
+		s.renderTemplate(w, r, http.StatusOK, "radicals.html", pick)
+	}
+}
+
+// renderTemplate parses and executes one of the files in 'template/'
+func (s *Server) renderTemplate(w http.ResponseWriter, r *http.Request, statusCode int, name string, data any) {
+	templatePath, _ := utils.GetAbsolutePath(filepath.Join("template", name))
+
+	// Parse template
+	tmpl, err := template.New(name).Funcs(templateFuncs(s.dictionaryFor(r.Context()))).ParseFiles(templatePath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	duration := time.Since(startTime)
-	s.logRequest(r, http.StatusOK, duration)
+	// Render into a buffer first, so errors can still change the status code
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		http.Error(w, fmt.Sprintf("Template rendering error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	body.WriteTo(w)
 }
 
-func (s *Server) staticFileHandler(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
-	statusCode := http.StatusOK
+func templateFuncs(dict *dictionary) template.FuncMap {
+	return template.FuncMap{
+		"tagDescription": dict.tagDescription,
+		"wordTags":       wordTags,
+		"ruby":           ruby,
+	}
+}
 
+func (s *Server) staticFileHandler(w http.ResponseWriter, r *http.Request) {
 	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 		path := strings.TrimPrefix(r.URL.Path, "/static/")
 		gzPath := filepath.Join("static", path+".gz")
@@ -99,9 +238,6 @@ func (s *Server) staticFileHandler(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Encoding", "gzip")
 			w.Header().Set("Content-Type", getContentType(path))
 			http.ServeFile(w, r, gzPath)
-
-			duration := time.Since(startTime)
-			s.logRequest(r, statusCode, duration)
 			return
 		}
 	}
@@ -109,13 +245,13 @@ func (s *Server) staticFileHandler(w http.ResponseWriter, r *http.Request) {
 	// Fallback to the original file server if no compressed version exists
 	// or if the client doesn't accept gzip
 	s.staticPrefix.ServeHTTP(w, r)
-
-	duration := time.Since(startTime)
-	s.logRequest(r, statusCode, duration)
 }
 
-func (s *Server) SetupRoutes() *http.ServeMux {
-	fmt.Printf("Setting up routes...\n")
+const metricsRoute = "GET /metrics"
+
+// SetupRoutes returns the handler of every page and API endpoint, wrapped in the middlewares every request goes through
+func (s *Server) SetupRoutes() http.Handler {
+	slog.Info("Setting up routes")
 
 	staticSystem := http.Dir("static")
 	staticServer := http.FileServer(staticSystem)
@@ -125,19 +261,60 @@ func (s *Server) SetupRoutes() *http.ServeMux {
 
 	mux.HandleFunc("GET /", s.indexHandler)
 	mux.HandleFunc("GET /search", s.searchHandler)
+	mux.HandleFunc("GET /word/{id}", s.wordHandler)
+	mux.HandleFunc("GET /kanji/{char}", s.kanjiHandler)
+	mux.HandleFunc("GET /analyze", s.analyzeHandler)
+	mux.HandleFunc("GET /radicals", s.radicalsHandler)
 	mux.HandleFunc("GET /static/", s.staticFileHandler)
 
-	return mux
+	// JSON API, shares the same search code as the HTML handlers
+	mux.HandleFunc("GET /api/v1/search", s.apiSearchHandler)
+	mux.HandleFunc("GET /api/v1/words/{id}", s.apiWordHandler)
+	mux.HandleFunc("GET /api/v1/analyze", s.apiAnalyzeHandler)
+	mux.HandleFunc("GET /api/v1/furigana", s.apiFuriganaHandler)
+	mux.HandleFunc("POST /api/v1/furigana", s.apiFuriganaHandler)
+	mux.HandleFunc("GET /api/v1/names", s.apiNamesSearchHandler)
+	mux.HandleFunc("GET /api/v1/kanji", s.apiKanjiSearchHandler)
+	mux.HandleFunc("GET /api/v1/kanji/{char}", s.apiKanjiHandler)
+	mux.HandleFunc("GET /api/v1/radicals", s.apiRadicalsHandler)
+	mux.HandleFunc("GET /api/v1/sentences", s.apiSentencesSearchHandler)
+	mux.HandleFunc("GET /api/v1/tags", s.apiTagsHandler)
+	mux.HandleFunc("GET /api/v1/stats", s.apiStatsHandler)
+
+	// Scraped by Prometheus and polled by healthchecks, only logged at debug level as they're requested every few seconds
+	mux.Handle(metricsRoute, s.metrics.handler())
+	mux.HandleFunc(healthRoute, s.healthHandler)
+	mux.HandleFunc(readyRoute, s.readyHandler)
+
+	// The access log sees the request the mux matched, so it can tell its route
+	return chain(mux, requestID, s.accessLog, recoverer, s.holdDictionary)
 }
 
-func NewServer(config types.ServerConfig) (*Server, error) {
-	db, err := database.NewDatabase(&config)
+// NewServer opens the current release, ctx cancels the wait for MongoDB while starting
+func NewServer(ctx context.Context, config types.ServerConfig) (*Server, error) {
+	release, err := database.CurrentRelease(config.DataDir, config.JmdictVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
+		return nil, fmt.Errorf("failed to find the current release: %w", err)
 	}
 
-	return &Server{
-		db:     db,
-		config: config,
-	}, nil
+	server := &Server{
+		config:  config,
+		cache:   newSearchCache(config.Search.CacheSize, config.Search.CacheTTL),
+		stop:    make(chan struct{}),
+		retired: make(map[*dictionary]struct{}),
+	}
+	server.metrics = newMetrics(server)
+
+	dict, err := openDictionary(ctx, &config, release, server.metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Serving release", "generation", release.Generation, "tags", len(dict.tags))
+	server.dict.Store(dict)
+
+	server.watching.Add(1)
+	go server.watchReleases()
+
+	return server, nil
 }
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/izquiratops/tango/common/config"
+	"github.com/izquiratops/tango/common/database"
+)
+
+// testServer serves a bolt release holding the tags and the words, indexed by their main form and reading
+func testServer(t *testing.T, tags []database.Tag, words ...database.Word) *Server {
+	t.Helper()
+	ctx := context.Background()
+
+	cfg := config.Defaults()
+	cfg.StorageBackend = string(database.BoltBackend)
+	cfg.DataDir = t.TempDir()
+	cfg.JmdictVersion = "3.6.1"
+
+	release := database.NewRelease(cfg.DataDir, cfg.JmdictVersion)
+	db, err := database.NewDatabase(ctx, &cfg, release)
+	if err != nil {
+		t.Fatalf("Error creating release: %v", err)
+	}
+
+	if err := db.Store.PutWords(ctx, words); err != nil {
+		t.Fatalf("Error writing words: %v", err)
+	}
+	if err := db.Store.PutTags(ctx, tags); err != nil {
+		t.Fatalf("Error writing tags: %v", err)
+	}
+	for _, word := range words {
+		doc := database.WordSearchable{ID: word.ID, KanaExact: []string{word.MainWord.Word}, Meanings: word.Meanings, Common: word.Common}
+		if word.MainWord.Reading != "" {
+			doc.KanjiExact, doc.KanaExact = []string{word.MainWord.Word}, []string{word.MainWord.Reading}
+		}
+		if err := db.BleveIndex.Index(doc.ID, doc); err != nil {
+			t.Fatalf("Error indexing word: %v", err)
+		}
+	}
+	if err := db.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		config:  cfg,
+		cache:   newSearchCache(cfg.Search.CacheSize, cfg.Search.CacheTTL),
+		stop:    make(chan struct{}),
+		retired: make(map[*dictionary]struct{}),
+	}
+	s.metrics = newMetrics(s)
+
+	dict, err := openDictionary(ctx, &cfg, release, s.metrics)
+	if err != nil {
+		t.Fatalf("Error opening release: %v", err)
+	}
+	s.dict.Store(dict)
+	t.Cleanup(func() { s.Close(ctx) })
+
+	return s
+}
+
+// inClientDir runs the test from the client folder, where the templates are looked up
+func inClientDir(t *testing.T) {
+	t.Helper()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+}
+
+func TestSearchHandlerRedirectsSentences(t *testing.T) {
+	inClientDir(t)
+	s := testServer(t, nil,
+		database.Word{ID: "1", MainWord: database.Furigana{Word: "日本", Reading: "にほん"}, Meanings: []string{"Japan"}},
+		database.Word{ID: "2", MainWord: database.Furigana{Word: "料理", Reading: "りょうり"}, Meanings: []string{"cooking"}},
+		database.Word{ID: "3", MainWord: database.Furigana{Word: "日本料理店", Reading: "にほんりょうりてん"}, Meanings: []string{"Japanese restaurant"}},
+		database.Word{ID: "4", MainWord: database.Furigana{Word: "の"}, Meanings: []string{"of"}},
+		database.Word{ID: "5", MainWord: database.Furigana{Word: "量", Reading: "りょう"}, Meanings: []string{"quantity"}},
+		database.Word{ID: "6", MainWord: database.Furigana{Word: "猫", Reading: "ねこ"}, Meanings: []string{"cat"}},
+	)
+
+	tests := []struct {
+		query    string
+		params   string // Added to the query string
+		redirect bool
+	}{
+		{"日本", "", false},              // A single word
+		{"japan", "", false},           // Romaji is never a sentence
+		{"日本料理", "", false},            // A compound, the beginning of 日本料理店
+		{"にほんりょう", "", false},          // Partial kana, the beginning of にほんりょうりてん
+		{"日本の料理", "", true},            // Particle between the words
+		{"日本、料理", "", true},            // Punctuation
+		{"日本猫", "", true},              // Two words that aren't a word together
+		{"日本の料理", "&tab=words", false}, // A tab was picked
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+tt.params, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/search?query="+url.QueryEscape(tt.query)+tt.params, nil))
+
+			location := w.Header().Get("Location")
+			if tt.redirect {
+				if want := "/analyze?text=" + url.QueryEscape(tt.query); w.Code != http.StatusFound || location != want {
+					t.Errorf("status %d, location %q, want a redirect to %q", w.Code, location, want)
+				}
+			} else if w.Code != http.StatusOK || location != "" {
+				t.Errorf("status %d, location %q, want the word search", w.Code, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/utils"
+)
+
+// fetchTags loads every JMdict tag into a map of tag name to its description
+func fetchTags(db *database.Database) (map[string]string, error) {
+	tags, err := db.Store.GetTags(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tagMap := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagMap[tag.Name] = tag.Description
+	}
+
+	return tagMap, nil
+}
+
+// tagDescription returns the description of a tag, or the tag itself when it's unknown
+func (d *dictionary) tagDescription(name string) string {
+	if description, ok := d.tags[name]; ok {
+		return description
+	}
+
+	return name
+}
+
+// wordTags returns the tags shown on a result card, the part of speech of every sense without duplicates
+func wordTags(word database.Word) []string {
+	var tags []string
+
+	for _, sense := range word.Senses {
+		for _, pos := range sense.PartOfSpeech {
+			if !utils.ContainsString(tags, pos) {
+				tags = append(tags, pos)
+			}
+		}
+	}
+
+	return tags
+}
+
+// usedTags collects the description of every tag referenced by the given words
+func (s *Server) usedTags(ctx context.Context, words ...database.Word) map[string]string {
+	return describeTags(s.dictionaryFor(ctx).tags, words...)
+}
+
+// describeTags picks the descriptions of the tags referenced by the given words out of every tag,
+// unknown tags are described by their name
+func describeTags(tags map[string]string, words ...database.Word) map[string]string {
+	used := make(map[string]string)
+
+	for _, word := range words {
+		for _, sense := range word.Senses {
+			for _, list := range [][]string{sense.PartOfSpeech, sense.Field, sense.Dialect, sense.Misc} {
+				for _, name := range list {
+					description, ok := tags[name]
+					if !ok {
+						description = name
+					}
+					used[name] = description
+				}
+			}
+		}
+	}
+
+	return used
+}
@@ -1,29 +1,12 @@
 package server
 
 import (
-	"fmt"
+	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strings"
-	"time"
 )
 
-func (s *Server) logRequest(r *http.Request, statusCode int, duration time.Duration) {
-	ip := r.RemoteAddr
-	query := r.URL.Query().Get("query")
-
-	// Format: [TIMESTAMP] METHOD PATH STATUS IP DURATION QUERY
-	logMsg := fmt.Sprintf("[%s] %s %s %d %s %v %s",
-		time.Now().Format("2006-01-02 15:04:05"),
-		r.Method,
-		r.URL.Path,
-		statusCode,
-		ip,
-		duration,
-		query)
-
-	fmt.Println(logMsg)
-}
-
 func getContentType(path string) string {
 	switch {
 	case strings.HasSuffix(path, ".css"):
@@ -36,3 +19,22 @@ func getContentType(path string) string {
 		return "application/octet-stream"
 	}
 }
+
+// writeJSON encodes v as the response body
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Failed to encode JSON response", "error", err)
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, statusCode int, code APIErrorCode, message string) {
+	writeJSON(w, statusCode, APIErrorResponse{
+		Error: APIError{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
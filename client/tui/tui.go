@@ -0,0 +1,277 @@
+// Package tui is the full-screen dictionary browser of 'tango browse'. It searches while the
+// query is typed, lists the results, and shows every sense of the selected word.
+package tui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/common/database"
+)
+
+// debounce is how long typing has to pause before searching, so every key doesn't run a search
+const debounce = 150 * time.Millisecond
+
+// Dictionary is what the browser searches, server.Lookup opens the local release read-only
+type Dictionary interface {
+	Search(ctx context.Context, searchTerm string, options server.SearchOptions) (*server.SearchResult, error)
+	TagDescriptions(words ...database.Word) map[string]string
+}
+
+type Options struct {
+	PageSize int
+	Title    string // Shown in the status line, e.g. the release being browsed
+	Query    string // Searched right away, empty to start with an empty input
+}
+
+// Run shows the browser until it's quit
+func Run(dict Dictionary, options Options, in io.Reader, out io.Writer) error {
+	program := tea.NewProgram(New(dict, options), tea.WithAltScreen(), tea.WithInput(in), tea.WithOutput(out))
+	_, err := program.Run()
+	return err
+}
+
+// Model is the state of the browser, see the bubbletea docs for how it's driven
+type Model struct {
+	dict    Dictionary
+	options Options
+
+	input  textinput.Model
+	detail viewport.Model // Senses of the selected word, scrolled on its own
+	width  int
+	height int
+
+	// seq numbers every search, only the answer to the latest one is shown
+	seq       int
+	query     string // Query of the latest search
+	page      int
+	searching bool
+
+	result   *server.SearchResult
+	err      error
+	selected int // Index of the selected word in result.Words
+
+	history   []string // Queries saved with enter, oldest first
+	historyAt int      // Query being shown while browsing the history, len(history) when not browsing it
+}
+
+// debounceMsg fires once typing pauses, seq is the search it was scheduled for
+type debounceMsg struct {
+	seq int
+}
+
+type resultMsg struct {
+	seq    int
+	result *server.SearchResult
+	err    error
+}
+
+func New(dict Dictionary, options Options) Model {
+	input := textinput.New()
+	input.Prompt = "🔍 "
+	input.Placeholder = "Search in English, romaji, kana or kanji"
+	input.Focus()
+
+	m := Model{
+		dict:    dict,
+		options: options,
+		input:   input,
+		detail:  viewport.New(0, 0),
+		page:    1,
+	}
+	if options.Query != "" {
+		m.input.SetValue(options.Query)
+		m.input.CursorEnd()
+		m.query = strings.TrimSpace(options.Query)
+		m.seq++
+		m.searching = true
+	}
+
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.searching {
+		return tea.Batch(textinput.Blink, m.search())
+	}
+	return textinput.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+
+	case debounceMsg:
+		if msg.seq != m.seq {
+			return m, nil
+		}
+		return m, m.search()
+
+	case resultMsg:
+		if msg.seq != m.seq {
+			// Answer to a query that was typed over
+			return m, nil
+		}
+
+		m.searching = false
+		m.result, m.err = msg.result, msg.err
+		if errors.Is(m.err, server.ErrNoResults) {
+			m.err = nil
+		}
+		m.selected = 0
+		m.renderDetail()
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.updateKey(msg)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		return m, tea.Quit
+
+	case "up":
+		if m.selected > 0 {
+			m.selected--
+			m.renderDetail()
+		}
+		return m, nil
+	case "down":
+		if m.result != nil && m.selected < len(m.result.Words)-1 {
+			m.selected++
+			m.renderDetail()
+		}
+		return m, nil
+
+	case "pgup":
+		m.detail.HalfViewUp()
+		return m, nil
+	case "pgdown":
+		m.detail.HalfViewDown()
+		return m, nil
+
+	case "ctrl+f":
+		if m.result != nil && m.result.From+len(m.result.Words) < int(m.result.Total) {
+			return m.changePage(m.page + 1)
+		}
+		return m, nil
+	case "ctrl+b":
+		if m.page > 1 {
+			return m.changePage(m.page - 1)
+		}
+		return m, nil
+
+	case "enter":
+		m.saveQuery()
+		return m, nil
+	case "ctrl+p":
+		if m.historyAt > 0 {
+			m.historyAt--
+			return m.setQuery(m.history[m.historyAt])
+		}
+		return m, nil
+	case "ctrl+n":
+		if m.historyAt < len(m.history)-1 {
+			m.historyAt++
+			return m.setQuery(m.history[m.historyAt])
+		}
+		if m.historyAt == len(m.history)-1 {
+			m.historyAt++
+			return m.setQuery("")
+		}
+		return m, nil
+	}
+
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() == before {
+		return m, cmd
+	}
+
+	// Typing leaves the history
+	m.historyAt = len(m.history)
+	return m, tea.Batch(cmd, m.queryChanged(false))
+}
+
+// setQuery replaces the input, and searches it right away
+func (m Model) setQuery(query string) (tea.Model, tea.Cmd) {
+	m.input.SetValue(query)
+	m.input.CursorEnd()
+	return m, m.queryChanged(true)
+}
+
+// queryChanged starts a search of the input from its first page, after the debounce unless now is set
+func (m *Model) queryChanged(now bool) tea.Cmd {
+	m.seq++
+	m.query = strings.TrimSpace(m.input.Value())
+	m.page = 1
+
+	if m.query == "" {
+		m.searching = false
+		m.result, m.err = nil, nil
+		m.renderDetail()
+		return nil
+	}
+
+	m.searching = true
+	if now {
+		return m.search()
+	}
+
+	seq := m.seq
+	return tea.Tick(debounce, func(time.Time) tea.Msg {
+		return debounceMsg{seq: seq}
+	})
+}
+
+func (m Model) changePage(page int) (tea.Model, tea.Cmd) {
+	m.seq++
+	m.page = page
+	m.searching = true
+	return m, m.search()
+}
+
+// search runs the latest query in the background
+func (m Model) search() tea.Cmd {
+	dict, seq := m.dict, m.seq
+	options := server.SearchOptions{Page: m.page, Size: m.options.PageSize}
+	query := m.query
+
+	return func() tea.Msg {
+		result, err := dict.Search(context.Background(), query, options)
+		return resultMsg{seq: seq, result: result, err: err}
+	}
+}
+
+// saveQuery adds the query to the history, unless it's the last one saved
+func (m *Model) saveQuery() {
+	if m.query != "" && (len(m.history) == 0 || m.history[len(m.history)-1] != m.query) {
+		m.history = append(m.history, m.query)
+	}
+	m.historyAt = len(m.history)
+}
+
+// selectedWord returns the word shown in the detail pane, if any
+func (m Model) selectedWord() (database.Word, bool) {
+	if m.result == nil || m.selected >= len(m.result.Words) {
+		return database.Word{}, false
+	}
+	return m.result.Words[m.selected], true
+}
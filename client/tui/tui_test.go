@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/izquiratops/tango/client/server"
+	"github.com/izquiratops/tango/common/database"
+)
+
+// fakeDictionary finds the words whose first meaning starts with the query
+type fakeDictionary struct {
+	words    []database.Word
+	searches []string
+}
+
+func (d *fakeDictionary) Search(ctx context.Context, searchTerm string, options server.SearchOptions) (*server.SearchResult, error) {
+	d.searches = append(d.searches, searchTerm)
+
+	result := &server.SearchResult{Query: searchTerm, Size: options.Size}
+	for _, word := range d.words {
+		if strings.HasPrefix(word.Meanings[0], searchTerm) {
+			result.Words = append(result.Words, word)
+		}
+	}
+	result.Total = uint64(len(result.Words))
+	if len(result.Words) == 0 {
+		return result, server.ErrNoResults
+	}
+	return result, nil
+}
+
+func (d *fakeDictionary) TagDescriptions(words ...database.Word) map[string]string {
+	return map[string]string{"v1": "Ichidan verb"}
+}
+
+func newTestModel() (Model, *fakeDictionary) {
+	dict := &fakeDictionary{words: []database.Word{
+		{ID: "1", MainWord: database.Furigana{Word: "食べる", Reading: "たべる"}, Meanings: []string{"to eat"},
+			Senses: []database.Sense{{PartOfSpeech: []string{"v1"}, Glosses: []string{"to eat"}}}},
+		{ID: "2", MainWord: database.Furigana{Word: "飲む", Reading: "のむ"}, Meanings: []string{"to drink"}},
+		{ID: "3", MainWord: database.Furigana{Word: "パン"}, Meanings: []string{"bread"}},
+	}}
+
+	m := New(dict, Options{PageSize: 20})
+	m = update(m, tea.WindowSizeMsg{Width: 100, Height: 20})
+	return m, dict
+}
+
+func update(m Model, msg tea.Msg) Model {
+	model, _ := m.Update(msg)
+	return model.(Model)
+}
+
+// search runs the pending search of the model, once typing paused
+func search(m Model) Model {
+	model, cmd := m.Update(debounceMsg{seq: m.seq})
+	return update(model.(Model), cmd())
+}
+
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestSearchAsYouType(t *testing.T) {
+	m, dict := newTestModel()
+
+	m = typeText(m, "to")
+	stale := m.seq
+	m = typeText(m, " ")
+	if !m.searching || len(dict.searches) != 0 {
+		t.Fatalf("searching = %v after %d searches, want a pending search and none run", m.searching, len(dict.searches))
+	}
+
+	// The pause after 'to' comes after the space was typed
+	if _, cmd := m.Update(debounceMsg{seq: stale}); cmd != nil {
+		t.Error("debounce of an older query should be dropped")
+	}
+
+	m = search(m)
+	if m.searching || len(m.result.Words) != 2 || dict.searches[0] != "to" {
+		t.Fatalf("searches %v, got %d words", dict.searches, len(m.result.Words))
+	}
+
+	// An answer arriving after the query changed isn't shown
+	m = typeText(m, "e")
+	m = update(m, resultMsg{seq: m.seq - 1, result: &server.SearchResult{}})
+	if len(m.result.Words) != 2 {
+		t.Errorf("stale result replaced the current one")
+	}
+
+	m = search(m)
+	if len(m.result.Words) != 1 || m.err != nil {
+		t.Errorf("got %d words and error %v, want 1 word", len(m.result.Words), m.err)
+	}
+
+	m = typeText(m, "zz")
+	m = search(m)
+	if len(m.result.Words) != 0 || m.err != nil || !strings.Contains(m.View(), `No results for "to ezz"`) {
+		t.Errorf("no results shown as %q", m.View())
+	}
+}
+
+func TestSelection(t *testing.T) {
+	m, _ := newTestModel()
+	m = search(typeText(m, "to"))
+
+	if !strings.Contains(m.detail.View(), "Ichidan verb") {
+		t.Errorf("detail pane %q doesn't describe the part of speech", m.detail.View())
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = update(m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.selected != 1 || !strings.Contains(m.detail.View(), "to drink") {
+		t.Errorf("selected %d, detail %q, want the last word", m.selected, m.detail.View())
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyUp})
+	if m.selected != 0 || !strings.Contains(m.View(), "1-2 of 2") {
+		t.Errorf("selected %d, view %q", m.selected, m.View())
+	}
+}
+
+func TestHistory(t *testing.T) {
+	m, _ := newTestModel()
+	for _, query := range []string{"to eat", "bread"} {
+		m = update(m, tea.KeyMsg{Type: tea.KeyCtrlU}) // Clears the input
+		m = search(typeText(m, query))
+		m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	steps := []struct {
+		key  tea.KeyType
+		want string
+	}{
+		{tea.KeyCtrlP, "bread"},
+		{tea.KeyCtrlP, "to eat"},
+		{tea.KeyCtrlP, "to eat"},
+		{tea.KeyCtrlN, "bread"},
+		{tea.KeyCtrlN, ""},
+	}
+	for _, step := range steps {
+		model, cmd := m.Update(tea.KeyMsg{Type: step.key})
+		m = model.(Model)
+		if cmd != nil {
+			m = update(m, cmd())
+		}
+
+		if m.input.Value() != step.want {
+			t.Fatalf("after %v the query is %q, want %q", step.key, m.input.Value(), step.want)
+		}
+	}
+
+	if m.result != nil {
+		t.Errorf("results of an empty query should be cleared")
+	}
+}
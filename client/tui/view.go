@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/izquiratops/tango/common/database"
+)
+
+const (
+	minListWidth = 24
+	// Lines around the panes: the input, a rule under it, a rule above the status line and the status line
+	chromeHeight = 4
+	help         = "↑↓ select · pgup/pgdn scroll · ctrl+f/b page · enter save · ctrl+p/n history · esc quit"
+)
+
+var (
+	wordStyle     = lipgloss.NewStyle().Bold(true)
+	readingStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	commonStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	tagStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+func (m Model) View() string {
+	if m.width == 0 {
+		// The size arrives right after starting
+		return ""
+	}
+
+	rule := dimStyle.Render(strings.Repeat("─", m.width))
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(m.listWidth()).Height(m.bodyHeight()).Render(m.listView()),
+		dimStyle.Render(strings.Repeat(" │\n", m.bodyHeight()-1)+" │"),
+		" ",
+		m.detail.View(),
+	)
+
+	return strings.Join([]string{m.input.View(), rule, body, rule, m.statusView()}, "\n")
+}
+
+func (m Model) listWidth() int {
+	return max(minListWidth, m.width/3)
+}
+
+func (m Model) detailWidth() int {
+	return max(1, m.width-m.listWidth()-3)
+}
+
+func (m Model) bodyHeight() int {
+	return max(1, m.height-chromeHeight)
+}
+
+// resize fits the input and the detail pane to the terminal
+func (m *Model) resize() {
+	m.input.Width = max(1, m.width-lipgloss.Width(m.input.Prompt)-1)
+	m.detail.Width = m.detailWidth()
+	m.detail.Height = m.bodyHeight()
+	m.renderDetail()
+}
+
+// listView is the results pane, scrolled so the selected word is always visible
+func (m Model) listView() string {
+	width := m.listWidth()
+
+	switch {
+	case m.query == "":
+		return dimStyle.Render("Type to search.\n\nWords are searched by meaning, romaji, kana or kanji, conjugated forms included.")
+	case m.err != nil:
+		return errorStyle.Width(width).Render("Search failed: " + m.err.Error())
+	case m.result == nil:
+		return dimStyle.Render("Searching…")
+	case len(m.result.Words) == 0:
+		return dimStyle.Width(width).Render(fmt.Sprintf("No results for %q", m.query))
+	}
+
+	offset := max(0, m.selected-m.bodyHeight()+1)
+	end := min(len(m.result.Words), offset+m.bodyHeight())
+
+	rows := make([]string, 0, end-offset)
+	for i := offset; i < end; i++ {
+		word := m.result.Words[i]
+		row := word.MainWord.Word
+		if word.MainWord.Reading != "" {
+			row += " " + word.MainWord.Reading
+		}
+		if word.Common {
+			row += " ●"
+		}
+		row = ansi.Truncate(" "+row, width, "…")
+
+		if i == m.selected {
+			row = selectedStyle.Width(width).Render(row)
+		}
+		rows = append(rows, row)
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func (m Model) statusView() string {
+	var parts []string
+	switch {
+	case m.searching:
+		parts = append(parts, "Searching…")
+	case m.result != nil && len(m.result.Words) > 0:
+		parts = append(parts, fmt.Sprintf("%d-%d of %d", m.result.From+1, m.result.From+len(m.result.Words), m.result.Total))
+	}
+	if m.options.Title != "" {
+		parts = append(parts, m.options.Title)
+	}
+	parts = append(parts, help)
+
+	return dimStyle.Render(ansi.Truncate(strings.Join(parts, " · "), m.width, "…"))
+}
+
+// renderDetail writes every sense of the selected word into the detail pane
+func (m *Model) renderDetail() {
+	word, ok := m.selectedWord()
+	if !ok {
+		m.detail.SetContent("")
+		return
+	}
+
+	width := m.detailWidth()
+	tags := m.dict.TagDescriptions(word)
+	describe := func(names []string) string {
+		descriptions := make([]string, len(names))
+		for i, name := range names {
+			descriptions[i] = tags[name]
+		}
+		return strings.Join(descriptions, ", ")
+	}
+	paragraph := func(indent int, style lipgloss.Style, text string) string {
+		return style.Width(width).PaddingLeft(indent).Render(text)
+	}
+
+	var b strings.Builder
+	heading := wordStyle.Render(word.MainWord.Word)
+	if word.MainWord.Reading != "" {
+		heading += " " + readingStyle.Render("【"+word.MainWord.Reading+"】")
+	}
+	if word.Common {
+		heading += " " + commonStyle.Render("common")
+	}
+	b.WriteString(heading + "\n")
+
+	if len(word.OtherForms) > 0 {
+		forms := make([]string, len(word.OtherForms))
+		for i, form := range word.OtherForms {
+			forms[i] = form.Word
+			if form.Reading != "" {
+				forms[i] += "【" + form.Reading + "】"
+			}
+		}
+		b.WriteString(paragraph(0, dimStyle, "Also written "+strings.Join(forms, "、")) + "\n")
+	}
+
+	for _, inflection := range m.result.Inflections {
+		if inflection.Word.ID == word.ID {
+			b.WriteString(paragraph(0, dimStyle, fmt.Sprintf("%s is the %s of %s", m.result.Query, inflection.Reason, inflection.Base)) + "\n")
+		}
+	}
+
+	for i, meaning := range word.Meanings {
+		b.WriteString("\n" + paragraph(0, lipgloss.NewStyle(), fmt.Sprintf("%d. %s", i+1, meaning)) + "\n")
+		if i >= len(word.Senses) {
+			continue
+		}
+
+		sense := word.Senses[i]
+		if len(sense.PartOfSpeech) > 0 {
+			b.WriteString(paragraph(3, tagStyle, describe(sense.PartOfSpeech)) + "\n")
+		}
+		if notes := describe(append(append(append([]string{}, sense.Field...), sense.Dialect...), sense.Misc...)); notes != "" {
+			b.WriteString(paragraph(3, dimStyle, notes) + "\n")
+		}
+		for _, info := range sense.Info {
+			b.WriteString(paragraph(3, dimStyle, info) + "\n")
+		}
+		for _, source := range sense.LanguageSource {
+			b.WriteString(paragraph(3, dimStyle, strings.TrimSpace("From "+source.Lang+" "+source.Text)) + "\n")
+		}
+		if len(sense.Related) > 0 {
+			b.WriteString(paragraph(3, dimStyle, "See also "+references(sense.Related)) + "\n")
+		}
+		if len(sense.Antonym) > 0 {
+			b.WriteString(paragraph(3, dimStyle, "Antonym of "+references(sense.Antonym)) + "\n")
+		}
+	}
+
+	if examples := m.result.Examples[word.ID]; len(examples) > 0 {
+		b.WriteString("\n" + wordStyle.Render("Examples") + "\n")
+		for _, sentence := range examples {
+			b.WriteString(paragraph(0, lipgloss.NewStyle(), sentence.Japanese) + "\n")
+			for _, english := range sentence.English {
+				b.WriteString(paragraph(0, dimStyle, english) + "\n")
+			}
+		}
+	}
+
+	m.detail.SetContent(strings.TrimRight(b.String(), "\n"))
+	m.detail.GotoTop()
+}
+
+func references(refs []database.CrossReference) string {
+	words := make([]string, len(refs))
+	for i, ref := range refs {
+		words[i] = ref.Word
+		if ref.Reading != "" {
+			words[i] += "【" + ref.Reading + "】"
+		}
+	}
+
+	return strings.Join(words, "、")
+}
@@ -1,43 +1,256 @@
+// Package config builds the configuration shared by the client and the importer. Every key is
+// read from four layers, each one overriding the previous: the defaults, a YAML file, the
+// environment and the command line flags.
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/izquiratops/tango/common/logging"
 	"github.com/izquiratops/tango/common/types"
+	"gopkg.in/yaml.v3"
 )
 
-const defaultMongoPort = 27017
+const configFileEnv = "TANGO_CONFIG"
 
-func LoadEnvironment(envMap map[EnvironmentType]string) (types.ServerConfig, error) {
-	jmdictVersion := os.Getenv("TANGO_VERSION")
-	if jmdictVersion == "" {
-		return types.ServerConfig{}, fmt.Errorf("TANGO_VERSION environment variable must be set")
+// mongoRunsLocalEnv picked the MongoDB host before mongo.host existed, without it the importer
+// connected to izquiratops.dev. It's ignored with a warning, and only rejected when it asks for
+// a local MongoDB and mongo.host doesn't say where.
+const mongoRunsLocalEnv = "TANGO_MONGO_RUNS_LOCAL"
+
+// Defaults is the first layer, good enough to run everything from a checkout
+func Defaults() types.ServerConfig {
+	return types.ServerConfig{
+		DataDir:        filepath.Join("..", "jmdict_source"),
+		StorageBackend: "mongo",
+		Listen:         "0.0.0.0:8080",
+		Mongo: types.MongoConfig{
+			Host: "localhost",
+			Port: 27017,
+		},
+		Search: types.SearchConfig{
+			DefaultSize: 20,
+			MaxSize:     100,
+			CacheSize:   1000,
+			CacheTTL:    10 * time.Minute,
+		},
+		LogFormat: logging.TextFormat,
+		LogLevel:  slog.LevelInfo,
+		HTTP: types.HTTPConfig{
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 20 * time.Second, // Docker waits 10 seconds by default before killing, see stop_grace_period
+		},
 	}
+}
+
+// Loader reads every layer of the configuration. Its flags are added to the flag set of the
+// binary, so Load must be called once they're parsed.
+type Loader struct {
+	configFile  string
+	printConfig bool
+	flagValues  map[string]string // Flags given on the command line, by key
+	sources     map[string]string // Layer each key was last set by, shown by -print-config
+}
 
-	var mongoRunsLocal bool
-	var mongoDomain string
-	if s := strings.ToLower(os.Getenv("TANGO_MONGO_RUNS_LOCAL")); s == "true" {
-		mongoRunsLocal = true
-		mongoDomain = envMap[LocalEnv]
-	} else {
-		mongoRunsLocal = false
-		mongoDomain = envMap[ServerEnv]
+// NewLoader adds -config, -print-config and a flag for every key to the flag set
+func NewLoader(flags *flag.FlagSet) *Loader {
+	l := &Loader{
+		flagValues: make(map[string]string),
+		sources:    make(map[string]string),
 	}
 
-	mongoURI := ""
-	mongoUser := os.Getenv("MONGO_INITDB_ROOT_USERNAME")
-	mongoPassword := os.Getenv("MONGO_INITDB_ROOT_PASSWORD")
-	if mongoUser != "" && mongoPassword != "" {
-		mongoURI = fmt.Sprintf("mongodb://%s:%s@%s:%d", mongoUser, mongoPassword, mongoDomain, defaultMongoPort)
-	} else {
-		mongoURI = fmt.Sprintf("mongodb://%s:%d", mongoDomain, defaultMongoPort)
+	flags.StringVar(&l.configFile, "config", "", "YAML configuration file (env "+configFileEnv+")")
+	flags.BoolVar(&l.printConfig, "print-config", false, "Print the configuration, with secrets redacted, and exit")
+	for _, s := range settings {
+		flags.Func(s.flagName(), s.usage+" (env "+s.env+")", func(value string) error {
+			l.flagValues[s.key] = value
+			return nil
+		})
 	}
 
-	return types.ServerConfig{
-		JmdictVersion:  jmdictVersion,
-		MongoURI:       mongoURI,
-		MongoRunsLocal: mongoRunsLocal,
-	}, nil
+	return l
+}
+
+// LoadOrPrint loads the configuration, and prints it to w when -print-config was given, in
+// which case there's nothing left for the command to do. An invalid configuration is still
+// printed, to see where the offending value came from.
+func (l *Loader) LoadOrPrint(w io.Writer) (config types.ServerConfig, printed bool, err error) {
+	config, err = l.Load()
+	if l.printConfig {
+		if printErr := l.Print(w, config); printErr != nil {
+			return config, true, errors.Join(err, printErr)
+		}
+		printed = true
+	}
+
+	return config, printed, err
+}
+
+// Load applies every layer over the defaults and validates the result. Errors name the key
+// and where its value came from.
+func (l *Loader) Load() (types.ServerConfig, error) {
+	config := Defaults()
+	for _, s := range settings {
+		l.sources[s.key] = "default"
+	}
+
+	configFile := l.configFile
+	if configFile == "" {
+		configFile = os.Getenv(configFileEnv)
+	}
+	if configFile != "" {
+		if err := l.loadFile(&config, configFile); err != nil {
+			return config, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := os.Getenv(s.env); value != "" {
+			if err := l.set(&config, s, value, "env "+s.env); err != nil {
+				return config, err
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := l.flagValues[s.key]; ok {
+			if err := l.set(&config, s, value, "flag -"+s.flagName()); err != nil {
+				return config, err
+			}
+		}
+	}
+
+	return config, l.validate(config)
+}
+
+func (l *Loader) set(config *types.ServerConfig, s setting, value string, source string) error {
+	if err := s.set(config, value); err != nil {
+		return fmt.Errorf("%s (from %s): %w", s.key, source, err)
+	}
+
+	l.sources[s.key] = source
+	return nil
+}
+
+// loadFile reads a YAML file, keys are nested the way they're dotted:
+//
+//	mongo:
+//	  host: localhost
+func (l *Loader) loadFile(config *types.ServerConfig, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	// An empty file has no content at all
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	byKey := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byKey[s.key] = s
+	}
+
+	var walk func(node *yaml.Node, prefix string) error
+	walk = func(node *yaml.Node, prefix string) error {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("config file %s line %d: %s must be a mapping of keys", path, node.Line, describeKey(prefix))
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			key := keyNode.Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			if valueNode.Kind == yaml.MappingNode {
+				if err := walk(valueNode, key); err != nil {
+					return err
+				}
+				continue
+			}
+
+			s, ok := byKey[key]
+			if !ok {
+				return fmt.Errorf("config file %s line %d: unknown key %q", path, keyNode.Line, key)
+			}
+			if valueNode.Kind != yaml.ScalarNode {
+				return fmt.Errorf("config file %s line %d: %s must be a single value", path, valueNode.Line, key)
+			}
+
+			source := fmt.Sprintf("config file %s line %d", path, valueNode.Line)
+			if err := l.set(config, s, valueNode.Value, source); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return walk(document.Content[0], "")
+}
+
+func describeKey(key string) string {
+	if key == "" {
+		return "the document"
+	}
+	return key
+}
+
+// validate checks what can't be checked one key at a time
+func (l *Loader) validate(config types.ServerConfig) error {
+	var errs []error
+	fail := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s (from %s): %s", key, l.sources[key], fmt.Sprintf(format, args...)))
+	}
+
+	if config.JmdictVersion == "" {
+		fail("version", "must be set, e.g. TANGO_VERSION=3.6.1")
+	}
+	if config.DataDir == "" {
+		fail("data_dir", "must not be empty")
+	}
+	if _, _, err := net.SplitHostPort(config.Listen); err != nil {
+		fail("listen", "must be a host:port address, got %q", config.Listen)
+	}
+	if config.StorageBackend == "mongo" && config.Mongo.URI == "" && config.Mongo.Host == "" {
+		fail("mongo.host", "must be set when mongo.uri isn't")
+	}
+	if config.Mongo.Port > 65535 {
+		fail("mongo.port", "must be a port number, got %d", config.Mongo.Port)
+	}
+	if value, ok := os.LookupEnv(mongoRunsLocalEnv); ok {
+		// Deployments can still have it in their .env, only a local MongoDB without a host is ambiguous
+		if strings.EqualFold(value, "true") && l.sources["mongo.host"] == "default" {
+			errs = append(errs, fmt.Errorf("env %s is no longer read, unset it and set mongo.host (env TANGO_MONGO_HOST) to the MongoDB server", mongoRunsLocalEnv))
+		} else {
+			slog.Warn("Ignoring deprecated environment variable, use mongo.host (env TANGO_MONGO_HOST) instead",
+				"env", mongoRunsLocalEnv, "mongo.host", config.Mongo.Host, "from", l.sources["mongo.host"])
+		}
+	}
+	if (config.Mongo.User == "") != (config.Mongo.Password == "") {
+		fail("mongo.password", "mongo.user and mongo.password must be set together")
+	}
+	if config.Search.DefaultSize > config.Search.MaxSize {
+		fail("search.default_size", "must not be larger than search.max_size (%d), got %d", config.Search.MaxSize, config.Search.DefaultSize)
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLoader(t *testing.T, args ...string) *Loader {
+	t.Helper()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	loader := NewLoader(flags)
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	return loader
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tango.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	path := writeConfigFile(t, `
+version: 3.6.1
+listen: 127.0.0.1:9000
+mongo:
+  host: db.example.com
+  port: 27018
+search:
+  max_size: 50
+`)
+	t.Setenv("TANGO_MONGO_HOST", "mongo")
+	t.Setenv("TANGO_SEARCH_CACHE_TTL", "1m")
+
+	config, err := newTestLoader(t, "-config", path, "-listen", "0.0.0.0:8081", "-search-cache-ttl", "2m").Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	if config.JmdictVersion != "3.6.1" || config.Mongo.Port != 27018 || config.Search.MaxSize != 50 {
+		t.Errorf("file values not applied: %+v", config)
+	}
+	if config.Mongo.Host != "mongo" {
+		t.Errorf("mongo.host = %q, env should override the file", config.Mongo.Host)
+	}
+	if config.Listen != "0.0.0.0:8081" || config.Search.CacheTTL != 2*time.Minute {
+		t.Errorf("listen = %q, search.cache_ttl = %v, flags should override everything", config.Listen, config.Search.CacheTTL)
+	}
+	if config.Search.DefaultSize != 20 || config.StorageBackend != "mongo" {
+		t.Errorf("defaults not kept: %+v", config)
+	}
+	if got := config.Mongo.ConnectionURI(); got != "mongodb://mongo:27018" {
+		t.Errorf("ConnectionURI() = %q", got)
+	}
+}
+
+func TestLoadErrorsNameTheKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		args    []string
+		wantErr string
+	}{
+		{"missing version", "", nil, nil, "version (from default): must be set"},
+		{"invalid env", "", map[string]string{"TANGO_VERSION": "3.6.1", "TANGO_MONGO_PORT": "abc"}, nil, "mongo.port (from env TANGO_MONGO_PORT)"},
+		{"invalid flag", "", map[string]string{"TANGO_VERSION": "3.6.1"}, []string{"-http-read-timeout", "soon"}, "http.read_timeout (from flag -http-read-timeout)"},
+		{"invalid file value", "version: 3.6.1\nstorage: sqlite\n", nil, nil, "storage (from config file"},
+		{"unknown file key", "version: 3.6.1\nmongo:\n  hots: x\n", nil, nil, `unknown key "mongo.hots"`},
+		{"invalid listen", "", map[string]string{"TANGO_VERSION": "3.6.1", "TANGO_LISTEN": "8080"}, nil, "listen (from env TANGO_LISTEN)"},
+		{"page size", "version: 3.6.1\nsearch:\n  default_size: 200\n", nil, nil, "search.default_size (from config file"},
+		{"old mongo env", "", map[string]string{"TANGO_VERSION": "3.6.1", "TANGO_MONGO_RUNS_LOCAL": "true"}, nil, "env TANGO_MONGO_RUNS_LOCAL is no longer read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeConfigFile(t, tt.file)}, args...)
+			}
+
+			_, err := newTestLoader(t, args...).Load()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadIgnoresOldMongoEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		host  string
+	}{
+		{"false", "false", ""},
+		{"true with a host", "true", "localhost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TANGO_VERSION", "3.6.1")
+			t.Setenv("TANGO_MONGO_RUNS_LOCAL", tt.value)
+			if tt.host != "" {
+				t.Setenv("TANGO_MONGO_HOST", tt.host)
+			}
+
+			config, err := newTestLoader(t).Load()
+			if err != nil {
+				t.Fatalf("Load() = %v, the old variable should only be warned about", err)
+			}
+			if config.Mongo.Host != "localhost" {
+				t.Errorf("mongo.host = %q, want localhost", config.Mongo.Host)
+			}
+		})
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	t.Setenv("TANGO_VERSION", "3.6.1")
+	t.Setenv("MONGO_INITDB_ROOT_USERNAME", "tango")
+	t.Setenv("MONGO_INITDB_ROOT_PASSWORD", "hunter2")
+	t.Setenv("TANGO_MONGO_URI", "mongodb://tango:hunter2@db:27017")
+
+	loader := newTestLoader(t)
+	config, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := loader.Print(&out, config); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(out.String(), "hunter2") {
+		t.Errorf("password printed:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "user: tango # env MONGO_INITDB_ROOT_USERNAME") {
+		t.Errorf("missing user and its source:\n%s", out.String())
+	}
+
+	// The dump is a config file itself
+	path := writeConfigFile(t, strings.ReplaceAll(out.String(), "xxxxx", "secret"))
+	os.Unsetenv("TANGO_VERSION")
+	reloaded, err := newTestLoader(t, "-config", path).Load()
+	if err != nil {
+		t.Fatalf("Load() of the printed config = %v", err)
+	}
+	if reloaded.JmdictVersion != "3.6.1" || reloaded.HTTP != config.HTTP || reloaded.Search != config.Search {
+		t.Errorf("printed config reloads as %+v", reloaded)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/izquiratops/tango/common/types"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+// Print writes the configuration as a YAML file that Load would read back, noting where every
+// value came from. Passwords are redacted.
+func (l *Loader) Print(w io.Writer, config types.ServerConfig) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := make(map[string]*yaml.Node)
+
+	for _, s := range settings {
+		parent := root
+		name := s.key
+		if section, field, ok := strings.Cut(s.key, "."); ok {
+			if sections[section] == nil {
+				sections[section] = &yaml.Node{Kind: yaml.MappingNode}
+				root.Content = append(root.Content, scalarNode(section), sections[section])
+			}
+			parent = sections[section]
+			name = field
+		}
+
+		value := scalarNode(s.get(&config))
+		if s.secret && value.Value != "" {
+			value = scalarNode(redacted)
+			if s.key == "mongo.uri" {
+				value = scalarNode(types.RedactURI(config.Mongo.URI))
+			}
+		}
+		if source := l.sources[s.key]; source != "" {
+			value.LineComment = source
+		}
+
+		parent.Content = append(parent.Content, scalarNode(name), value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("error encoding config: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
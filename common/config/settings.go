@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izquiratops/tango/common/logging"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// setting is one configuration key, with the names it goes by in every layer
+type setting struct {
+	key    string // Dotted path in the config file, e.g. "mongo.port"
+	env    string // Environment variable
+	usage  string
+	secret bool // Hidden by -print-config
+	set    func(config *types.ServerConfig, value string) error
+	get    func(config *types.ServerConfig) string
+}
+
+// flagName is the key as a command line flag, e.g. "mongo-port"
+func (s setting) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// settings is every configuration key, in the order -print-config shows them
+var settings = []setting{
+	stringSetting("version", "TANGO_VERSION", "JMdict version to serve or import, e.g. 3.6.1",
+		func(c *types.ServerConfig) *string { return &c.JmdictVersion }),
+	stringSetting("data_dir", "TANGO_DATA_DIR", "Folder with the dictionary sources and every imported release",
+		func(c *types.ServerConfig) *string { return &c.DataDir }),
+	{
+		key:   "storage",
+		env:   "TANGO_STORAGE",
+		usage: "Store keeping the documents: mongo or bolt",
+		set: func(c *types.ServerConfig, value string) error {
+			value = strings.ToLower(value)
+			if value != "mongo" && value != "bolt" {
+				return fmt.Errorf("must be either 'mongo' or 'bolt', got %q", value)
+			}
+			c.StorageBackend = value
+			return nil
+		},
+		get: func(c *types.ServerConfig) string { return c.StorageBackend },
+	},
+	stringSetting("listen", "TANGO_LISTEN", "Address the client serves on",
+		func(c *types.ServerConfig) *string { return &c.Listen }),
+
+	{
+		key:    "mongo.uri",
+		env:    "TANGO_MONGO_URI",
+		usage:  "MongoDB connection URI, replaces the host, port, user and password",
+		secret: true,
+		set: func(c *types.ServerConfig, value string) error {
+			c.Mongo.URI = value
+			return nil
+		},
+		get: func(c *types.ServerConfig) string { return c.Mongo.URI },
+	},
+	stringSetting("mongo.host", "TANGO_MONGO_HOST", "MongoDB host",
+		func(c *types.ServerConfig) *string { return &c.Mongo.Host }),
+	intSetting("mongo.port", "TANGO_MONGO_PORT", "MongoDB port",
+		func(c *types.ServerConfig) *int { return &c.Mongo.Port }),
+	// Same variables as the MongoDB container, so both can share the .env file
+	stringSetting("mongo.user", "MONGO_INITDB_ROOT_USERNAME", "MongoDB user",
+		func(c *types.ServerConfig) *string { return &c.Mongo.User }),
+	{
+		key:    "mongo.password",
+		env:    "MONGO_INITDB_ROOT_PASSWORD",
+		usage:  "MongoDB password",
+		secret: true,
+		set: func(c *types.ServerConfig, value string) error {
+			c.Mongo.Password = value
+			return nil
+		},
+		get: func(c *types.ServerConfig) string { return c.Mongo.Password },
+	},
+
+	intSetting("search.default_size", "TANGO_SEARCH_DEFAULT_SIZE", "Results per page when the request doesn't ask for a size",
+		func(c *types.ServerConfig) *int { return &c.Search.DefaultSize }),
+	intSetting("search.max_size", "TANGO_SEARCH_MAX_SIZE", "Largest page size a request can ask for",
+		func(c *types.ServerConfig) *int { return &c.Search.MaxSize }),
+	intSetting("search.cache_size", "TANGO_SEARCH_CACHE_SIZE", "Searches kept in memory",
+		func(c *types.ServerConfig) *int { return &c.Search.CacheSize }),
+	durationSetting("search.cache_ttl", "TANGO_SEARCH_CACHE_TTL", "Time a search result is served from memory",
+		func(c *types.ServerConfig) *time.Duration { return &c.Search.CacheTTL }),
+
+	{
+		key:   "log.format",
+		env:   "TANGO_LOG_FORMAT",
+		usage: "Log output: text or json",
+		set: func(c *types.ServerConfig, value string) (err error) {
+			c.LogFormat, err = logging.ParseFormat(value)
+			return err
+		},
+		get: func(c *types.ServerConfig) string { return string(c.LogFormat) },
+	},
+	{
+		key:   "log.level",
+		env:   "TANGO_LOG_LEVEL",
+		usage: "Lowest level logged: debug, info, warn or error",
+		set: func(c *types.ServerConfig, value string) (err error) {
+			c.LogLevel, err = logging.ParseLevel(value)
+			return err
+		},
+		get: func(c *types.ServerConfig) string { return strings.ToLower(c.LogLevel.String()) },
+	},
+
+	durationSetting("http.read_timeout", "TANGO_HTTP_READ_TIMEOUT", "Time to read a whole request",
+		func(c *types.ServerConfig) *time.Duration { return &c.HTTP.ReadTimeout }),
+	durationSetting("http.write_timeout", "TANGO_HTTP_WRITE_TIMEOUT", "Time to write a response",
+		func(c *types.ServerConfig) *time.Duration { return &c.HTTP.WriteTimeout }),
+	durationSetting("http.idle_timeout", "TANGO_HTTP_IDLE_TIMEOUT", "Time a keep-alive connection waits for the next request",
+		func(c *types.ServerConfig) *time.Duration { return &c.HTTP.IdleTimeout }),
+	durationSetting("http.shutdown_timeout", "TANGO_SHUTDOWN_TIMEOUT", "Time given to in-flight requests on SIGINT or SIGTERM",
+		func(c *types.ServerConfig) *time.Duration { return &c.HTTP.ShutdownTimeout }),
+}
+
+func stringSetting(key, env, usage string, field func(*types.ServerConfig) *string) setting {
+	return setting{
+		key:   key,
+		env:   env,
+		usage: usage,
+		set: func(c *types.ServerConfig, value string) error {
+			*field(c) = value
+			return nil
+		},
+		get: func(c *types.ServerConfig) string { return *field(c) },
+	}
+}
+
+// intSetting only accepts positive numbers, every count and size is
+func intSetting(key, env, usage string, field func(*types.ServerConfig) *int) setting {
+	return setting{
+		key:   key,
+		env:   env,
+		usage: usage,
+		set: func(c *types.ServerConfig, value string) error {
+			number, err := strconv.Atoi(value)
+			if err != nil || number <= 0 {
+				return fmt.Errorf("must be a positive number, got %q", value)
+			}
+			*field(c) = number
+			return nil
+		},
+		get: func(c *types.ServerConfig) string { return strconv.Itoa(*field(c)) },
+	}
+}
+
+func durationSetting(key, env, usage string, field func(*types.ServerConfig) *time.Duration) setting {
+	return setting{
+		key:   key,
+		env:   env,
+		usage: usage,
+		set: func(c *types.ServerConfig, value string) error {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("must be a positive duration such as '30s', got %q", value)
+			}
+			*field(c) = duration
+			return nil
+		},
+		get: func(c *types.ServerConfig) string { return field(c).String() },
+	}
+}
@@ -0,0 +1,279 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltWordsBucket     = []byte("words")
+	boltKanjiBucket     = []byte("kanji")
+	boltRadicalsBucket  = []byte("radicals")
+	boltNamesBucket     = []byte("names")
+	boltSentencesBucket = []byte("sentences")
+	boltTagsBucket      = []byte("tags")
+
+	boltBuckets = [][]byte{boltWordsBucket, boltKanjiBucket, boltRadicalsBucket, boltNamesBucket, boltSentencesBucket, boltTagsBucket}
+)
+
+// BoltStore keeps every document in a single bbolt file, so the dictionary can be served without MongoDB
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	// Timeout avoids blocking forever when another process holds the file lock
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening bbolt file: %v", err)
+	}
+
+	store := &BoltStore{db: db}
+	if err := store.createBuckets(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// OpenBoltStore opens the file of a finished import read-only, other processes can still read it
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error opening bbolt file: %v", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (b *BoltStore) createBuckets() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("error creating bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// boltCodec turns the documents of a bucket into keys and values, and back
+type boltCodec[T any] struct {
+	kind   string // Name of the documents in errors
+	key    func(T) string
+	encode func(T) ([]byte, error)
+	decode func(data []byte, key []byte) (T, error)
+}
+
+// jsonCodec stores documents as JSON under the given key
+func jsonCodec[T any](kind string, key func(T) string) boltCodec[T] {
+	return boltCodec[T]{
+		kind: kind,
+		key:  key,
+		encode: func(document T) ([]byte, error) {
+			return json.Marshal(document)
+		},
+		decode: func(data []byte, _ []byte) (T, error) {
+			var document T
+			err := json.Unmarshal(data, &document)
+			return document, err
+		},
+	}
+}
+
+var (
+	wordCodec     = jsonCodec("word", func(w Word) string { return w.ID })
+	kanjiCodec    = jsonCodec("kanji", func(k Kanji) string { return k.Literal })
+	radicalCodec  = jsonCodec("radical", func(r Radical) string { return r.Literal })
+	nameCodec     = jsonCodec("name", func(n Name) string { return n.ID })
+	sentenceCodec = jsonCodec("sentence", func(s Sentence) string { return s.ID })
+	// Tags are stored as their bare description
+	tagCodec = boltCodec[Tag]{
+		kind:   "tag",
+		key:    func(t Tag) string { return t.Name },
+		encode: func(t Tag) ([]byte, error) { return []byte(t.Description), nil },
+		decode: func(data []byte, key []byte) (Tag, error) {
+			return Tag{Name: string(key), Description: string(data)}, nil
+		},
+	}
+)
+
+// boltGet decodes the documents stored under the keys, skipping unknown keys. A missing bucket
+// holds nothing: releases imported before a bucket existed don't have it, and read-only
+// stores can't create it.
+func boltGet[T any](db *bolt.DB, name []byte, codec boltCodec[T], keys []string) ([]T, error) {
+	var results []T
+
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(name)
+		if bucket == nil {
+			return nil
+		}
+
+		for _, key := range keys {
+			data := bucket.Get([]byte(key))
+			if data == nil {
+				continue
+			}
+
+			document, err := codec.decode(data, []byte(key))
+			if err != nil {
+				return fmt.Errorf("failed to decode %s %v: %w", codec.kind, key, err)
+			}
+			results = append(results, document)
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// boltGetAll decodes every document of the bucket, a missing bucket holds nothing
+func boltGetAll[T any](db *bolt.DB, name []byte, codec boltCodec[T]) ([]T, error) {
+	var results []T
+
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(name)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(key, data []byte) error {
+			document, err := codec.decode(data, key)
+			if err != nil {
+				return fmt.Errorf("failed to decode %s %s: %w", codec.kind, key, err)
+			}
+
+			results = append(results, document)
+			return nil
+		})
+	})
+
+	return results, err
+}
+
+func boltPut[T any](db *bolt.DB, name []byte, codec boltCodec[T], documents []T) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(name)
+		if err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", name, err)
+		}
+
+		for _, document := range documents {
+			key := codec.key(document)
+			data, err := codec.encode(document)
+			if err != nil {
+				return fmt.Errorf("error marshalling %s %v: %w", codec.kind, key, err)
+			}
+
+			if err := bucket.Put([]byte(key), data); err != nil {
+				return fmt.Errorf("error writing %s %v: %w", codec.kind, key, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (b *BoltStore) GetWords(ctx context.Context, ids []string) ([]Word, error) {
+	return boltGet(b.db, boltWordsBucket, wordCodec, ids)
+}
+
+func (b *BoltStore) GetWord(ctx context.Context, id string) (*Word, error) {
+	words, err := b.GetWords(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &words[0], nil
+}
+
+func (b *BoltStore) PutWords(ctx context.Context, words []Word) error {
+	return boltPut(b.db, boltWordsBucket, wordCodec, words)
+}
+
+func (b *BoltStore) GetKanji(ctx context.Context, literals []string) ([]Kanji, error) {
+	return boltGet(b.db, boltKanjiBucket, kanjiCodec, literals)
+}
+
+func (b *BoltStore) GetKanjiCharacter(ctx context.Context, literal string) (*Kanji, error) {
+	kanji, err := b.GetKanji(ctx, []string{literal})
+	if err != nil {
+		return nil, err
+	}
+	if len(kanji) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &kanji[0], nil
+}
+
+func (b *BoltStore) PutKanji(ctx context.Context, kanji []Kanji) error {
+	return boltPut(b.db, boltKanjiBucket, kanjiCodec, kanji)
+}
+
+func (b *BoltStore) GetRadicals(ctx context.Context) ([]Radical, error) {
+	return boltGetAll(b.db, boltRadicalsBucket, radicalCodec)
+}
+
+func (b *BoltStore) PutRadicals(ctx context.Context, radicals []Radical) error {
+	return boltPut(b.db, boltRadicalsBucket, radicalCodec, radicals)
+}
+
+func (b *BoltStore) GetNames(ctx context.Context, ids []string) ([]Name, error) {
+	return boltGet(b.db, boltNamesBucket, nameCodec, ids)
+}
+
+func (b *BoltStore) PutNames(ctx context.Context, names []Name) error {
+	return boltPut(b.db, boltNamesBucket, nameCodec, names)
+}
+
+func (b *BoltStore) GetSentences(ctx context.Context, ids []string) ([]Sentence, error) {
+	return boltGet(b.db, boltSentencesBucket, sentenceCodec, ids)
+}
+
+func (b *BoltStore) PutSentences(ctx context.Context, sentences []Sentence) error {
+	return boltPut(b.db, boltSentencesBucket, sentenceCodec, sentences)
+}
+
+func (b *BoltStore) GetTags(ctx context.Context) ([]Tag, error) {
+	return boltGetAll(b.db, boltTagsBucket, tagCodec)
+}
+
+func (b *BoltStore) PutTags(ctx context.Context, tags []Tag) error {
+	return boltPut(b.db, boltTagsBucket, tagCodec, tags)
+}
+
+func (b *BoltStore) Drop(ctx context.Context) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltBuckets {
+			if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+				return fmt.Errorf("error dropping bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.createBuckets()
+}
+
+// Ping fails once the file is closed, it's local so there's nothing else to check
+func (b *BoltStore) Ping(ctx context.Context) error {
+	return b.db.View(func(tx *bolt.Tx) error { return nil })
+}
+
+func (b *BoltStore) Close(ctx context.Context) error {
+	return b.db.Close()
+}
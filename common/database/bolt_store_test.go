@@ -0,0 +1,246 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "jmdict_test.db"))
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	words := []Word{
+		{ID: "1", MainWord: Furigana{Word: "暖かい", Reading: "あたたかい"}, Meanings: []string{"warm"}},
+		{ID: "2", MainWord: Furigana{Word: "パン"}, Meanings: []string{"bread"}},
+	}
+	if err := store.PutWords(ctx, words); err != nil {
+		t.Fatalf("Error writing words: %v", err)
+	}
+
+	got, err := store.GetWords(ctx, []string{"2", "unknown", "1"})
+	if err != nil {
+		t.Fatalf("Error reading words: %v", err)
+	}
+	if !reflect.DeepEqual(got, []Word{words[1], words[0]}) {
+		t.Errorf("GetWords() = %v, want %v", got, []Word{words[1], words[0]})
+	}
+
+	if _, err := store.GetWord(ctx, "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetWord() error = %v, want %v", err, ErrNotFound)
+	}
+
+	tags := []Tag{{Name: "v5k", Description: "Godan verb with 'ku' ending"}}
+	if err := store.PutTags(ctx, tags); err != nil {
+		t.Fatalf("Error writing tags: %v", err)
+	}
+
+	gotTags, err := store.GetTags(ctx)
+	if err != nil || !reflect.DeepEqual(gotTags, tags) {
+		t.Errorf("GetTags() = %v, %v, want %v", gotTags, err, tags)
+	}
+
+	if err := store.Drop(ctx); err != nil {
+		t.Fatalf("Error dropping store: %v", err)
+	}
+	if _, err := store.GetWord(ctx, "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetWord() after Drop error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestBoltStoreKanji(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "jmdict_test.db"))
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	kanji := []Kanji{
+		{Literal: "語", OnReadings: []string{"ゴ"}, KunReadings: []string{"かた.る"}, Meanings: []string{"word"}, StrokeCount: 14, Radical: 149},
+		{Literal: "日", OnReadings: []string{"ニチ", "ジツ"}, KunReadings: []string{"ひ"}, Meanings: []string{"day", "sun"}, StrokeCount: 4, Radical: 72},
+	}
+	if err := store.PutKanji(ctx, kanji); err != nil {
+		t.Fatalf("Error writing kanji: %v", err)
+	}
+
+	got, err := store.GetKanji(ctx, []string{"日", "犬", "語"})
+	if err != nil {
+		t.Fatalf("Error reading kanji: %v", err)
+	}
+	if !reflect.DeepEqual(got, []Kanji{kanji[1], kanji[0]}) {
+		t.Errorf("GetKanji() = %v, want %v", got, []Kanji{kanji[1], kanji[0]})
+	}
+
+	if _, err := store.GetKanjiCharacter(ctx, "犬"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetKanjiCharacter() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestKanjiRadicalCharacter(t *testing.T) {
+	testCases := []struct {
+		radical  int
+		expected string
+	}{
+		{1, "⼀"},
+		{72, "⽇"},
+		{214, "⿕"},
+		{0, ""},
+	}
+
+	for _, tc := range testCases {
+		if got := (Kanji{Radical: tc.radical}).RadicalCharacter(); got != tc.expected {
+			t.Errorf("RadicalCharacter(%d) = %q, want %q", tc.radical, got, tc.expected)
+		}
+	}
+}
+
+func TestBoltStoreNames(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "jmdict_test.db"))
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	names := []Name{
+		{ID: "5000000", MainWord: Furigana{Word: "田中", Reading: "たなか"}, Types: []string{"surname"}, Translations: []string{"Tanaka"}},
+	}
+	if err := store.PutNames(ctx, names); err != nil {
+		t.Fatalf("Error writing names: %v", err)
+	}
+
+	got, err := store.GetNames(ctx, []string{"unknown", "5000000"})
+	if err != nil || !reflect.DeepEqual(got, names) {
+		t.Errorf("GetNames() = %v, %v, want %v", got, err, names)
+	}
+}
+
+func TestBoltStoreRadicals(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "jmdict_test.db"))
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	radicals := []Radical{{Literal: "一", StrokeCount: 1}, {Literal: "口", StrokeCount: 3}}
+	if err := store.PutRadicals(ctx, radicals); err != nil {
+		t.Fatalf("Error writing radicals: %v", err)
+	}
+
+	got, err := store.GetRadicals(ctx)
+	if err != nil || !reflect.DeepEqual(got, radicals) {
+		t.Errorf("GetRadicals() = %v, %v, want %v", got, err, radicals)
+	}
+}
+
+func TestBoltStoreSentences(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "jmdict_test.db"))
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	sentences := []Sentence{
+		{ID: "74172", Japanese: "パンを食べる。", English: []string{"I eat bread."}, WordIDs: []string{"1049180", "1358280"}},
+	}
+	if err := store.PutSentences(ctx, sentences); err != nil {
+		t.Fatalf("Error writing sentences: %v", err)
+	}
+
+	got, err := store.GetSentences(ctx, []string{"unknown", "74172"})
+	if err != nil || !reflect.DeepEqual(got, sentences) {
+		t.Errorf("GetSentences() = %v, %v, want %v", got, err, sentences)
+	}
+}
+
+func TestOpenBoltStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jmdict_test.db")
+
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	word := Word{ID: "1", MainWord: Furigana{Word: "パン"}, Meanings: []string{"bread"}}
+	if err := store.PutWords(ctx, []Word{word}); err != nil {
+		t.Fatalf("Error writing words: %v", err)
+	}
+	store.Close(ctx)
+
+	// Read-only stores share the file
+	first, err := OpenBoltStore(path)
+	if err != nil {
+		t.Fatalf("Error opening store read-only: %v", err)
+	}
+	defer first.Close(ctx)
+	second, err := OpenBoltStore(path)
+	if err != nil {
+		t.Fatalf("Error opening store read-only twice: %v", err)
+	}
+	defer second.Close(ctx)
+
+	if got, err := second.GetWord(ctx, "1"); err != nil || !reflect.DeepEqual(*got, word) {
+		t.Errorf("GetWord() = %v, %v, want %v", got, err, word)
+	}
+	if err := first.PutWords(ctx, []Word{word}); err == nil {
+		t.Error("PutWords() on a read-only store should fail")
+	}
+
+	if _, err := OpenBoltStore(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("OpenBoltStore() of a missing file should fail")
+	}
+}
+
+func TestOpenBoltStoreMissingBuckets(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jmdict_test.db")
+
+	// A release imported before kanji, radicals, names and sentences were stored
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucket(boltWordsBucket)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := OpenBoltStore(path)
+	if err != nil {
+		t.Fatalf("Error opening store read-only: %v", err)
+	}
+	defer store.Close(ctx)
+
+	if radicals, err := store.GetRadicals(ctx); err != nil || len(radicals) != 0 {
+		t.Errorf("GetRadicals() = %v, %v, want nothing", radicals, err)
+	}
+	if tags, err := store.GetTags(ctx); err != nil || len(tags) != 0 {
+		t.Errorf("GetTags() = %v, %v, want nothing", tags, err)
+	}
+	if names, err := store.GetNames(ctx, []string{"1"}); err != nil || len(names) != 0 {
+		t.Errorf("GetNames() = %v, %v, want nothing", names, err)
+	}
+	if _, err := store.GetKanjiCharacter(ctx, "校"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetKanjiCharacter() error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
-	"strings"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/izquiratops/tango/common/types"
 
@@ -14,53 +16,244 @@ import (
 	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
 	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	mongoPingAttempts = 6
+	mongoPingTimeout  = 5 * time.Second
+	mongoPingBackoff  = time.Second // Doubled after every failed attempt, so about a minute in total
 )
 
 type Database struct {
-	MongoWords *mongo.Collection
-	MongoTags  *mongo.Collection
-	BleveIndex bleve.Index
+	Store          Store
+	BleveIndex     bleve.Index
+	KanjiIndex     bleve.Index
+	NamesIndex     bleve.Index // Kept apart from the words, so names don't change how words rank
+	SentencesIndex bleve.Index
+	Release        Release
+}
+
+// NewDatabase opens (or creates) the index and the store of a release, to import into it
+func NewDatabase(ctx context.Context, config *types.ServerConfig, release Release) (*Database, error) {
+	if err := os.MkdirAll(release.Path(), 0755); err != nil {
+		return nil, fmt.Errorf("error creating release folder: %v", err)
+	}
+
+	return setupDatabase(ctx, config, release, false)
 }
 
-func NewDatabase(config *types.ServerConfig) (*Database, error) {
-	// Collections doesn't allow '.'s on their names
-	mongoCollectionName := strings.Replace(config.JmdictVersion, ".", "_", -1)
+// OpenDatabase opens the index and the store of a promoted release read-only. Read-only files
+// are only locked for writing, so the server and any number of commands can open the same release.
+func OpenDatabase(ctx context.Context, config *types.ServerConfig, release Release) (*Database, error) {
+	if _, err := os.Stat(release.Path()); err != nil {
+		return nil, fmt.Errorf("error opening release folder: %v", err)
+	}
+
+	return setupDatabase(ctx, config, release, true)
+}
+
+func setupDatabase(ctx context.Context, config *types.ServerConfig, release Release, readOnly bool) (*Database, error) {
+	store, err := setupStore(ctx, config, release, readOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Store initialized", "backend", config.StorageBackend, "read_only", readOnly)
+
+	setupIndex := setupBleve
+	if readOnly {
+		setupIndex = openBleveReadOnly
+	}
+
+	bleveIndex, err := setupIndex(release.BlevePath(), wordsIndexMapping)
+	if err != nil {
+		store.Close(context.Background())
+		return nil, err
+	}
 
-	mongoDB, err := setupMongoDB(config.MongoURI, mongoCollectionName)
+	kanjiIndex, err := setupIndex(release.KanjiBlevePath(), kanjiIndexMapping)
 	if err != nil {
+		bleveIndex.Close()
+		store.Close(context.Background())
 		return nil, err
 	}
 
-	fmt.Printf("MongoDB initialized successfully\n")
+	// Names share the words mapping, so the same queries work on both
+	namesIndex, err := setupIndex(release.NamesBlevePath(), wordsIndexMapping)
+	if err != nil {
+		kanjiIndex.Close()
+		bleveIndex.Close()
+		store.Close(context.Background())
+		return nil, err
+	}
 
-	bleveIndex, err := setupBleve(config.JmdictVersion)
+	sentencesIndex, err := setupIndex(release.SentencesBlevePath(), sentencesIndexMapping)
 	if err != nil {
+		namesIndex.Close()
+		kanjiIndex.Close()
+		bleveIndex.Close()
+		store.Close(context.Background())
 		return nil, err
 	}
 
-	fmt.Printf("Bleve initialized successfully\n")
+	slog.Info("Bleve initialized")
 
 	return &Database{
-		MongoWords: mongoDB.Collection("words"),
-		MongoTags:  mongoDB.Collection("tags"),
-		BleveIndex: bleveIndex,
+		Store:          store,
+		BleveIndex:     bleveIndex,
+		KanjiIndex:     kanjiIndex,
+		NamesIndex:     namesIndex,
+		SentencesIndex: sentencesIndex,
+		Release:        release,
 	}, nil
 }
 
-func setupMongoDB(mongoURI string, collectionName string) (*mongo.Database, error) {
+// Close releases the Bleve indexes and the Store
+func (db *Database) Close(ctx context.Context) error {
+	bleveErr := db.BleveIndex.Close()
+	kanjiErr := db.KanjiIndex.Close()
+	namesErr := db.NamesIndex.Close()
+	sentencesErr := db.SentencesIndex.Close()
+	storeErr := db.Store.Close(ctx)
+
+	return errors.Join(bleveErr, kanjiErr, namesErr, sentencesErr, storeErr)
+}
+
+// DeleteRelease removes every file and document of a release. It must not be open.
+func DeleteRelease(config *types.ServerConfig, release Release) error {
 	ctx := context.Background()
 
+	// Its files sit right in the data folder, they're removed by hand
+	if release.Legacy() {
+		return errors.New("the legacy release can't be deleted")
+	}
+
+	// Files are removed anyway, only MongoDB needs to be told
+	if StorageBackend(config.StorageBackend) == MongoBackend {
+		store, err := setupStore(ctx, config, release, false)
+		if err != nil {
+			return err
+		}
+
+		dropErr := store.Drop(ctx)
+		if err := errors.Join(dropErr, store.Close(ctx)); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(release.Path())
+}
+
+func setupStore(ctx context.Context, config *types.ServerConfig, release Release, readOnly bool) (Store, error) {
+	switch StorageBackend(config.StorageBackend) {
+	case MongoBackend:
+		mongoDB, err := setupMongoDB(ctx, config.Mongo.ConnectionURI(), release.MongoDatabaseName())
+		if err != nil {
+			return nil, err
+		}
+
+		return NewMongoStore(mongoDB), nil
+	case BoltBackend:
+		if readOnly {
+			return OpenBoltStore(release.BoltPath())
+		}
+		return NewBoltStore(release.BoltPath())
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.StorageBackend)
+	}
+}
+
+func setupMongoDB(ctx context.Context, mongoURI string, collectionName string) (*mongo.Database, error) {
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to MongoDB: %v", err)
 	}
 
+	// Connect doesn't reach the server, an unreachable MongoDB would only show up on the first query
+	if err := pingMongo(ctx, client); err != nil {
+		// The startup context may be cancelled already, disconnecting shouldn't depend on it
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("error reaching MongoDB: %w", err)
+	}
+
 	return client.Database(collectionName), nil
 }
 
-func setupBleve(dbVersion string) (bleve.Index, error) {
+// pingMongo waits for MongoDB to answer, it may still be starting when both containers come up together.
+// It gives up as soon as the context is done, e.g. when the server is stopped while waiting.
+func pingMongo(ctx context.Context, client *mongo.Client) error {
+	backoff := mongoPingBackoff
+
+	var err error
+	for attempt := 1; attempt <= mongoPingAttempts; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, mongoPingTimeout)
+		err = client.Ping(pingCtx, readpref.Primary())
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if attempt < mongoPingAttempts {
+			slog.Warn("MongoDB isn't answering yet", "attempt", attempt, "retry_in", backoff, "error", err)
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+	}
+
+	return fmt.Errorf("no answer after %d attempts: %w", mongoPingAttempts, err)
+}
+
+func setupBleve(blevePath string, buildMapping func() (*mapping.IndexMappingImpl, error)) (bleve.Index, error) {
+	indexMapping, err := buildMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	bleveIndex, err := bleve.New(blevePath, indexMapping)
+	if err != nil {
+		bleveIndex, err = bleve.Open(blevePath)
+		if err != nil {
+			return nil, fmt.Errorf("error creating/opening Bleve index: %v", err)
+		}
+	}
+
+	return bleveIndex, nil
+}
+
+// openBleveReadOnly opens an existing index, the mapping is the one stored with it
+func openBleveReadOnly(blevePath string, buildMapping func() (*mapping.IndexMappingImpl, error)) (bleve.Index, error) {
+	// The legacy release only has words, its other indexes are empty
+	if blevePath == "" {
+		indexMapping, err := buildMapping()
+		if err != nil {
+			return nil, err
+		}
+		return bleve.NewMemOnly(indexMapping)
+	}
+
+	bleveIndex, err := bleve.OpenUsing(blevePath, map[string]interface{}{
+		"read_only": true,
+		// Same as the store, don't block forever when an importer still has it open for writing
+		"bolt_timeout": "5s",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error opening Bleve index %s: %v", blevePath, err)
+	}
+
+	return bleveIndex, nil
+}
+
+func newIndexMapping() (*mapping.IndexMappingImpl, error) {
 	indexMapping := bleve.NewIndexMapping()
 
 	if err := indexMapping.AddCustomAnalyzer("custom_english", map[string]interface{}{
@@ -73,6 +266,15 @@ func setupBleve(dbVersion string) (bleve.Index, error) {
 		return nil, err
 	}
 
+	return indexMapping, nil
+}
+
+func wordsIndexMapping() (*mapping.IndexMappingImpl, error) {
+	indexMapping, err := newIndexMapping()
+	if err != nil {
+		return nil, err
+	}
+
 	documentMapping := bleve.NewDocumentMapping()
 
 	// English indexes
@@ -98,18 +300,76 @@ func setupBleve(dbVersion string) (bleve.Index, error) {
 	kanjiCharMapping.Analyzer = cjk.AnalyzerName
 	documentMapping.AddFieldMappingsAt("kanji_char", kanjiCharMapping)
 
+	// Romaji indexes, readings are stored already normalized as Hepburn
+	romajiMapping := bleve.NewTextFieldMapping()
+	romajiMapping.Analyzer = keyword.Name
+	documentMapping.AddFieldMappingsAt("romaji", romajiMapping)
+
+	// Lets lists of words put the common ones first
+	commonMapping := bleve.NewBooleanFieldMapping()
+	documentMapping.AddFieldMappingsAt("common", commonMapping)
+
 	// Default mapping
 	indexMapping.AddDocumentMapping("_default", documentMapping)
 
-	bleveFilename := fmt.Sprintf("jmdict_%v.bleve", dbVersion)
-	blevePath := filepath.Join("..", "jmdict_source", bleveFilename)
-	bleveIndex, err := bleve.New(blevePath, indexMapping)
+	return indexMapping, nil
+}
+
+func kanjiIndexMapping() (*mapping.IndexMappingImpl, error) {
+	indexMapping, err := newIndexMapping()
 	if err != nil {
-		bleveIndex, err = bleve.Open(blevePath)
-		if err != nil {
-			return nil, fmt.Errorf("error creating/opening Bleve index: %v", err)
-		}
+		return nil, err
 	}
 
-	return bleveIndex, nil
+	documentMapping := bleve.NewDocumentMapping()
+
+	literalMapping := bleve.NewTextFieldMapping()
+	literalMapping.Analyzer = keyword.Name
+	documentMapping.AddFieldMappingsAt("literal", literalMapping)
+
+	meaningsMapping := bleve.NewTextFieldMapping()
+	meaningsMapping.Analyzer = "custom_english"
+	documentMapping.AddFieldMappingsAt("meanings", meaningsMapping)
+
+	readingsMapping := bleve.NewTextFieldMapping()
+	readingsMapping.Analyzer = keyword.Name
+	documentMapping.AddFieldMappingsAt("readings", readingsMapping)
+
+	// Radical lookup, kanji are found by their components and listed by stroke count
+	componentsMapping := bleve.NewTextFieldMapping()
+	componentsMapping.Analyzer = keyword.Name
+	documentMapping.AddFieldMappingsAt("components", componentsMapping)
+
+	strokeCountMapping := bleve.NewNumericFieldMapping()
+	documentMapping.AddFieldMappingsAt("stroke_count", strokeCountMapping)
+
+	indexMapping.AddDocumentMapping("_default", documentMapping)
+
+	return indexMapping, nil
+}
+
+func sentencesIndexMapping() (*mapping.IndexMappingImpl, error) {
+	indexMapping, err := newIndexMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	documentMapping := bleve.NewDocumentMapping()
+
+	japaneseMapping := bleve.NewTextFieldMapping()
+	japaneseMapping.Analyzer = cjk.AnalyzerName
+	documentMapping.AddFieldMappingsAt("japanese", japaneseMapping)
+
+	englishMapping := bleve.NewTextFieldMapping()
+	englishMapping.Analyzer = "custom_english"
+	documentMapping.AddFieldMappingsAt("english", englishMapping)
+
+	// Examples of a word are found by its JMdict ID
+	wordsMapping := bleve.NewTextFieldMapping()
+	wordsMapping.Analyzer = keyword.Name
+	documentMapping.AddFieldMappingsAt("words", wordsMapping)
+
+	indexMapping.AddDocumentMapping("_default", documentMapping)
+
+	return indexMapping, nil
 }
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestPingMongoStopsWhenCancelled(t *testing.T) {
+	// Nothing listens on port 1, every ping fails right away
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = pingMongo(ctx, client)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("pingMongo = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// The first retry alone waits a second
+	if elapsed := time.Since(start); elapsed > mongoPingBackoff {
+		t.Errorf("pingMongo returned after %v, want it to stop with the context", elapsed)
+	}
+}
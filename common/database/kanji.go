@@ -0,0 +1,35 @@
+package database
+
+// Kanji is a single character from Kanjidic2
+type Kanji struct {
+	Literal      string   `json:"literal" bson:"_id"`
+	OnReadings   []string `json:"onReadings" bson:"on_readings"`   // Chinese readings, in katakana
+	KunReadings  []string `json:"kunReadings" bson:"kun_readings"` // Japanese readings, okurigana after the '.'
+	Nanori       []string `json:"nanori" bson:"nanori"`            // Readings only used in names
+	Meanings     []string `json:"meanings" bson:"meanings"`
+	StrokeCount  int      `json:"strokeCount" bson:"stroke_count"`
+	Grade        int      `json:"grade,omitempty" bson:"grade,omitempty"`         // School grade it's taught in, 0 when unknown
+	JLPT         int      `json:"jlpt,omitempty" bson:"jlpt,omitempty"`           // Level on the old 4 to 1 JLPT scale, 0 when unknown
+	Frequency    int      `json:"frequency,omitempty" bson:"frequency,omitempty"` // Rank among the 2500 most used kanji, 0 when unknown
+	Radical      int      `json:"radical" bson:"radical"`                         // Classical (Kangxi) radical number
+	RadicalNames []string `json:"radicalNames,omitempty" bson:"radical_names,omitempty"`
+	Components   []string `json:"components,omitempty" bson:"components,omitempty"` // Radicals it's made of, from KRADFILE
+}
+
+// RadicalCharacter returns the Kangxi radical of the kanji, as found in the Unicode 'Kangxi Radicals' block
+func (k Kanji) RadicalCharacter() string {
+	if k.Radical < 1 || k.Radical > 214 {
+		return ""
+	}
+
+	return string(rune(0x2F00 + k.Radical - 1))
+}
+
+// KanjiSearchable is the document indexed in the kanji Bleve index
+type KanjiSearchable struct {
+	Literal     string   `json:"literal"`
+	Meanings    []string `json:"meanings"`
+	Readings    []string `json:"readings"` // On and kun readings in hiragana, without okurigana markers
+	Components  []string `json:"components"`
+	StrokeCount int      `json:"stroke_count"`
+}
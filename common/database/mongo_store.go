@@ -0,0 +1,282 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type MongoStore struct {
+	client    *mongo.Client
+	words     *mongo.Collection
+	kanji     *mongo.Collection
+	radicals  *mongo.Collection
+	names     *mongo.Collection
+	sentences *mongo.Collection
+	tags      *mongo.Collection
+}
+
+func NewMongoStore(mongoDB *mongo.Database) *MongoStore {
+	return &MongoStore{
+		client:    mongoDB.Client(),
+		words:     mongoDB.Collection("words"),
+		kanji:     mongoDB.Collection("kanji"),
+		radicals:  mongoDB.Collection("radicals"),
+		names:     mongoDB.Collection("names"),
+		sentences: mongoDB.Collection("sentences"),
+		tags:      mongoDB.Collection("tags"),
+	}
+}
+
+func (m *MongoStore) GetWords(ctx context.Context, ids []string) ([]Word, error) {
+	filter := bson.M{
+		"_id": bson.M{
+			"$in": ids,
+		},
+	}
+
+	cursor, err := m.words.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents in MongoDB: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []Word
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to iterate over cursor: %w", err)
+	}
+
+	return results, nil
+}
+
+func (m *MongoStore) GetWord(ctx context.Context, id string) (*Word, error) {
+	var result Word
+	err := m.words.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document in MongoDB: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (m *MongoStore) PutWords(ctx context.Context, words []Word) error {
+	models := make([]mongo.WriteModel, 0, len(words))
+	for _, word := range words {
+		models = append(models, mongo.NewInsertOneModel().SetDocument(word))
+	}
+
+	if _, err := m.words.BulkWrite(ctx, models); err != nil {
+		return fmt.Errorf("error writing to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) GetKanji(ctx context.Context, literals []string) ([]Kanji, error) {
+	filter := bson.M{
+		"_id": bson.M{
+			"$in": literals,
+		},
+	}
+
+	cursor, err := m.kanji.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find kanji in MongoDB: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []Kanji
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to iterate over cursor: %w", err)
+	}
+
+	return results, nil
+}
+
+func (m *MongoStore) GetKanjiCharacter(ctx context.Context, literal string) (*Kanji, error) {
+	var result Kanji
+	err := m.kanji.FindOne(ctx, bson.M{"_id": literal}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find kanji in MongoDB: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (m *MongoStore) PutKanji(ctx context.Context, kanji []Kanji) error {
+	models := make([]mongo.WriteModel, 0, len(kanji))
+	for _, k := range kanji {
+		models = append(models, mongo.NewInsertOneModel().SetDocument(k))
+	}
+
+	if _, err := m.kanji.BulkWrite(ctx, models); err != nil {
+		return fmt.Errorf("error writing kanji to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) GetRadicals(ctx context.Context) ([]Radical, error) {
+	cursor, err := m.radicals.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find radicals in MongoDB: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var radicals []Radical
+	if err := cursor.All(ctx, &radicals); err != nil {
+		return nil, fmt.Errorf("failed to decode radicals: %w", err)
+	}
+
+	return radicals, nil
+}
+
+func (m *MongoStore) PutRadicals(ctx context.Context, radicals []Radical) error {
+	documents := make([]interface{}, 0, len(radicals))
+	for _, radical := range radicals {
+		documents = append(documents, radical)
+	}
+
+	if _, err := m.radicals.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("error writing radicals to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) GetNames(ctx context.Context, ids []string) ([]Name, error) {
+	filter := bson.M{
+		"_id": bson.M{
+			"$in": ids,
+		},
+	}
+
+	cursor, err := m.names.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find names in MongoDB: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []Name
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to iterate over cursor: %w", err)
+	}
+
+	return results, nil
+}
+
+func (m *MongoStore) PutNames(ctx context.Context, names []Name) error {
+	models := make([]mongo.WriteModel, 0, len(names))
+	for _, name := range names {
+		models = append(models, mongo.NewInsertOneModel().SetDocument(name))
+	}
+
+	if _, err := m.names.BulkWrite(ctx, models); err != nil {
+		return fmt.Errorf("error writing names to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) GetSentences(ctx context.Context, ids []string) ([]Sentence, error) {
+	filter := bson.M{
+		"_id": bson.M{
+			"$in": ids,
+		},
+	}
+
+	cursor, err := m.sentences.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sentences in MongoDB: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []Sentence
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to iterate over cursor: %w", err)
+	}
+
+	return results, nil
+}
+
+func (m *MongoStore) PutSentences(ctx context.Context, sentences []Sentence) error {
+	models := make([]mongo.WriteModel, 0, len(sentences))
+	for _, sentence := range sentences {
+		models = append(models, mongo.NewInsertOneModel().SetDocument(sentence))
+	}
+
+	if _, err := m.sentences.BulkWrite(ctx, models); err != nil {
+		return fmt.Errorf("error writing sentences to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) GetTags(ctx context.Context) ([]Tag, error) {
+	cursor, err := m.tags.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tags in MongoDB: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tags []Tag
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (m *MongoStore) PutTags(ctx context.Context, tags []Tag) error {
+	documents := make([]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		documents = append(documents, tag)
+	}
+
+	if _, err := m.tags.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("error writing tags to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) Drop(ctx context.Context) error {
+	if err := m.words.Drop(ctx); err != nil {
+		return fmt.Errorf("error dropping Words collection: %w", err)
+	}
+	if err := m.kanji.Drop(ctx); err != nil {
+		return fmt.Errorf("error dropping Kanji collection: %w", err)
+	}
+	if err := m.radicals.Drop(ctx); err != nil {
+		return fmt.Errorf("error dropping Radicals collection: %w", err)
+	}
+	if err := m.names.Drop(ctx); err != nil {
+		return fmt.Errorf("error dropping Names collection: %w", err)
+	}
+	if err := m.sentences.Drop(ctx); err != nil {
+		return fmt.Errorf("error dropping Sentences collection: %w", err)
+	}
+	if err := m.tags.Drop(ctx); err != nil {
+		return fmt.Errorf("error dropping Tags collection: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MongoStore) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx, readpref.Primary())
+}
+
+func (m *MongoStore) Close(ctx context.Context) error {
+	return m.client.Disconnect(ctx)
+}
@@ -0,0 +1,22 @@
+package database
+
+// Name is a proper name from JMnedict: a surname, a place, a company...
+type Name struct {
+	ID           string     `json:"id" bson:"_id"`
+	MainWord     Furigana   `json:"mainWord" bson:"main_word"`
+	OtherForms   []Furigana `json:"otherForms" bson:"other_forms"`
+	Types        []string   `json:"types" bson:"types"`               // Name type tags, e.g. 'surname' or 'place'
+	Translations []string   `json:"translations" bson:"translations"` // Romanized names or English translations
+}
+
+// NameSearchable is the document indexed in the names Bleve index. It uses the same
+// fields as WordSearchable, so names are found with the same queries as words.
+type NameSearchable struct {
+	ID         string   `json:"id"`
+	KanjiExact []string `json:"kanji_exact"`
+	KanjiChar  []string `json:"kanji_char"`
+	KanaExact  []string `json:"kana_exact"`
+	KanaChar   []string `json:"kana_char"`
+	Meanings   []string `json:"meanings"` // Translations
+	Romaji     []string `json:"romaji"`
+}
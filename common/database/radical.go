@@ -0,0 +1,7 @@
+package database
+
+// Radical is one of the components used to look up kanji by their parts, from RADKFILE
+type Radical struct {
+	Literal     string `json:"literal" bson:"_id"`
+	StrokeCount int    `json:"strokeCount" bson:"stroke_count"`
+}
@@ -0,0 +1,306 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	currentPointer  = "current"  // File holding the generation being served
+	previousPointer = "previous" // File holding the generation kept for rollbacks
+	manifestFile    = "manifest.json"
+
+	// LegacyGeneration is the index written before imports had generations, see CurrentRelease
+	LegacyGeneration = "legacy"
+)
+
+// ErrNoRelease is returned when no import has been promoted yet
+var ErrNoRelease = errors.New("no release has been promoted yet, run the importer first")
+
+// Release is one import of a JMdict version. Every import writes a new generation,
+// and the 'current' pointer file decides which one is served.
+//
+//	<data dir>/jmdict_3.6.1/
+//	├── current                  # "20250101T120000Z-3f9a1c2b"
+//	├── previous                 # "20241201T120000Z-90d2e4a7"
+//	└── 20250101T120000Z-3f9a1c2b/
+//	    ├── manifest.json
+//	    ├── words.bleve/
+//	    ├── kanji.bleve/
+//	    ├── names.bleve/
+//	    ├── sentences.bleve/
+//	    └── words.db             # bolt backend only
+//
+// Imports from before generations existed only wrote <data dir>/jmdict_3.6.1.bleve (and
+// jmdict_3.6.1.db), they're served as the legacy generation until something is promoted.
+type Release struct {
+	DataDir       string // Folder holding the releases of every JMdict version
+	JmdictVersion string
+	Generation    string
+}
+
+// Manifest describes a finished import, it's written next to the index before promoting it
+type Manifest struct {
+	JmdictVersion string    `json:"jmdictVersion"`
+	Generation    string    `json:"generation"`
+	DictDate      string    `json:"dictDate"`
+	Words         int       `json:"words"`
+	Kanji         int       `json:"kanji"`
+	Radicals      int       `json:"radicals"`
+	Names         int       `json:"names"`
+	Sentences     int       `json:"sentences"`
+	Tags          int       `json:"tags"`
+	ImportedAt    time.Time `json:"importedAt"`
+	Duration      float64   `json:"durationSeconds"`
+}
+
+// NewRelease returns a release with a fresh generation, nothing is written until something is imported into it.
+// Generations sort by the time they were started, the random suffix keeps imports started in the
+// same second apart.
+func NewRelease(dataDir string, jmdictVersion string) Release {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return Release{
+		DataDir:       dataDir,
+		JmdictVersion: jmdictVersion,
+		Generation:    time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix),
+	}
+}
+
+// ReleasesDir is the folder holding every generation of a JMdict version
+func ReleasesDir(dataDir string, jmdictVersion string) string {
+	return filepath.Join(dataDir, fmt.Sprintf("jmdict_%v", jmdictVersion))
+}
+
+// Legacy tells if the release is an import from before generations existed
+func (r Release) Legacy() bool {
+	return r.Generation == LegacyGeneration
+}
+
+// Path is the folder of the generation, the data folder itself for the legacy one
+func (r Release) Path() string {
+	if r.Legacy() {
+		return r.DataDir
+	}
+	return filepath.Join(ReleasesDir(r.DataDir, r.JmdictVersion), r.Generation)
+}
+
+func (r Release) BlevePath() string {
+	if r.Legacy() {
+		return filepath.Join(r.DataDir, fmt.Sprintf("jmdict_%v.bleve", r.JmdictVersion))
+	}
+	return filepath.Join(r.Path(), "words.bleve")
+}
+
+// KanjiBlevePath, NamesBlevePath and SentencesBlevePath are empty for the legacy release, it only has words
+
+func (r Release) KanjiBlevePath() string {
+	if r.Legacy() {
+		return ""
+	}
+	return filepath.Join(r.Path(), "kanji.bleve")
+}
+
+func (r Release) NamesBlevePath() string {
+	if r.Legacy() {
+		return ""
+	}
+	return filepath.Join(r.Path(), "names.bleve")
+}
+
+func (r Release) SentencesBlevePath() string {
+	if r.Legacy() {
+		return ""
+	}
+	return filepath.Join(r.Path(), "sentences.bleve")
+}
+
+func (r Release) BoltPath() string {
+	if r.Legacy() {
+		return filepath.Join(r.DataDir, fmt.Sprintf("jmdict_%v.db", r.JmdictVersion))
+	}
+	return filepath.Join(r.Path(), "words.db")
+}
+
+// MongoDatabaseName is the database holding this generation's collections
+func (r Release) MongoDatabaseName() string {
+	// Collections doesn't allow '.'s on their names
+	name := strings.Replace(r.JmdictVersion, ".", "_", -1)
+	if r.Legacy() {
+		return name
+	}
+	return name + "_" + r.Generation
+}
+
+func (r Release) WriteManifest(manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(r.Path(), manifestFile), data)
+}
+
+func (r Release) ReadManifest() (Manifest, error) {
+	var manifest Manifest
+
+	data, err := os.ReadFile(filepath.Join(r.Path(), manifestFile))
+	if err != nil {
+		return manifest, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("error decoding manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// CurrentRelease returns the generation being served for a JMdict version, or ErrNoRelease.
+// Until a generation is promoted, an index from an older importer is served as the legacy release.
+func CurrentRelease(dataDir string, jmdictVersion string) (Release, error) {
+	release, err := readPointer(dataDir, jmdictVersion, currentPointer)
+	if !errors.Is(err, ErrNoRelease) {
+		return release, err
+	}
+
+	legacy := Release{DataDir: dataDir, JmdictVersion: jmdictVersion, Generation: LegacyGeneration}
+	if _, statErr := os.Stat(legacy.BlevePath()); statErr == nil {
+		return legacy, nil
+	}
+	return release, err
+}
+
+// PreviousRelease returns the generation kept for rollbacks, or ErrNoRelease
+func PreviousRelease(dataDir string, jmdictVersion string) (Release, error) {
+	return readPointer(dataDir, jmdictVersion, previousPointer)
+}
+
+// PromoteRelease makes the release the current one, keeping the old current one as previous
+func PromoteRelease(release Release) error {
+	current, err := CurrentRelease(release.DataDir, release.JmdictVersion)
+	if err == nil && current.Generation != release.Generation {
+		if err := writePointer(release.DataDir, release.JmdictVersion, previousPointer, current.Generation); err != nil {
+			return err
+		}
+	} else if err != nil && !errors.Is(err, ErrNoRelease) {
+		return err
+	}
+
+	// Renaming the pointer is atomic, running servers either see the old or the new generation
+	return writePointer(release.DataDir, release.JmdictVersion, currentPointer, release.Generation)
+}
+
+// RollbackRelease swaps the current and previous releases, returning the one now being served
+func RollbackRelease(dataDir string, jmdictVersion string) (Release, error) {
+	current, err := CurrentRelease(dataDir, jmdictVersion)
+	if err != nil {
+		return Release{}, err
+	}
+
+	previous, err := PreviousRelease(dataDir, jmdictVersion)
+	if err != nil {
+		return Release{}, fmt.Errorf("nothing to roll back to: %w", err)
+	}
+
+	if err := writePointer(dataDir, jmdictVersion, currentPointer, previous.Generation); err != nil {
+		return Release{}, err
+	}
+	if err := writePointer(dataDir, jmdictVersion, previousPointer, current.Generation); err != nil {
+		return Release{}, err
+	}
+
+	return previous, nil
+}
+
+// StaleReleases lists every finished generation on disk that is neither current nor previous,
+// like old imports or one that failed to be promoted. Generations without a manifest are left
+// alone: an import may still be writing into them.
+func StaleReleases(dataDir string, jmdictVersion string) ([]Release, error) {
+	keep := make(map[string]bool)
+	for _, pointer := range []string{currentPointer, previousPointer} {
+		if release, err := readPointer(dataDir, jmdictVersion, pointer); err == nil {
+			keep[release.Generation] = true
+		}
+	}
+
+	entries, err := os.ReadDir(ReleasesDir(dataDir, jmdictVersion))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var stale []Release
+	for _, entry := range entries {
+		if !entry.IsDir() || keep[entry.Name()] {
+			continue
+		}
+
+		release := Release{
+			DataDir:       dataDir,
+			JmdictVersion: jmdictVersion,
+			Generation:    entry.Name(),
+		}
+		if _, err := os.Stat(filepath.Join(release.Path(), manifestFile)); err != nil {
+			slog.Info("Keeping release without manifest, it's still being imported or the import was killed", "generation", release.Generation)
+			continue
+		}
+
+		stale = append(stale, release)
+	}
+
+	return stale, nil
+}
+
+func readPointer(dataDir string, jmdictVersion string, pointer string) (Release, error) {
+	data, err := os.ReadFile(filepath.Join(ReleasesDir(dataDir, jmdictVersion), pointer))
+	if errors.Is(err, os.ErrNotExist) {
+		return Release{}, ErrNoRelease
+	}
+	if err != nil {
+		return Release{}, fmt.Errorf("error reading %v release: %w", pointer, err)
+	}
+
+	generation := strings.TrimSpace(string(data))
+	if generation == "" {
+		return Release{}, ErrNoRelease
+	}
+
+	return Release{
+		DataDir:       dataDir,
+		JmdictVersion: jmdictVersion,
+		Generation:    generation,
+	}, nil
+}
+
+func writePointer(dataDir string, jmdictVersion string, pointer string, generation string) error {
+	path := filepath.Join(ReleasesDir(dataDir, jmdictVersion), pointer)
+
+	if err := writeFileAtomic(path, []byte(generation+"\n")); err != nil {
+		return fmt.Errorf("error writing %v release: %w", pointer, err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes into a temporary file first, then renames it over the destination
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/types"
+)
+
+func TestNewReleaseGenerations(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		generation := NewRelease(t.TempDir(), "3.6.1").Generation
+		if seen[generation] {
+			t.Fatalf("generation %q was returned twice", generation)
+		}
+		seen[generation] = true
+	}
+}
+
+func TestStaleReleases(t *testing.T) {
+	dataDir := t.TempDir()
+
+	var releases []Release
+	for range 4 {
+		release := NewRelease(dataDir, "3.6.1")
+		if err := os.MkdirAll(release.Path(), 0755); err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+	}
+
+	// The first three finished and were promoted in turn, the last one is still importing
+	for _, release := range releases[:3] {
+		if err := release.WriteManifest(Manifest{Generation: release.Generation}); err != nil {
+			t.Fatal(err)
+		}
+		if err := PromoteRelease(release); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stale, err := StaleReleases(dataDir, "3.6.1")
+	if err != nil {
+		t.Fatalf("StaleReleases() error = %v", err)
+	}
+	if len(stale) != 1 || stale[0].Generation != releases[0].Generation {
+		t.Errorf("StaleReleases() = %v, want only %v", stale, releases[0].Generation)
+	}
+}
+
+func TestLegacyRelease(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	config := &types.ServerConfig{StorageBackend: string(BoltBackend)}
+
+	if _, err := CurrentRelease(dataDir, "3.6.1"); !errors.Is(err, ErrNoRelease) {
+		t.Fatalf("CurrentRelease() error = %v, want ErrNoRelease", err)
+	}
+
+	// What older importers wrote: an index and a store right in the data folder
+	legacy := Release{DataDir: dataDir, JmdictVersion: "3.6.1", Generation: LegacyGeneration}
+	index, err := bleve.New(legacy.BlevePath(), bleve.NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Index("1", map[string]string{"kana_exact": "たべる"}); err != nil {
+		t.Fatal(err)
+	}
+	index.Close()
+	store, err := NewBoltStore(legacy.BoltPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close(ctx)
+
+	current, err := CurrentRelease(dataDir, "3.6.1")
+	if err != nil || current != legacy {
+		t.Fatalf("CurrentRelease() = %v, %v, want the legacy release", current, err)
+	}
+
+	db, err := OpenDatabase(ctx, config, current)
+	if err != nil {
+		t.Fatalf("OpenDatabase() error = %v", err)
+	}
+	defer db.Close(ctx)
+	if count, _ := db.BleveIndex.DocCount(); count != 1 {
+		t.Errorf("words = %d, want 1", count)
+	}
+	if count, err := db.KanjiIndex.DocCount(); err != nil || count != 0 {
+		t.Errorf("kanji = %d, %v, want an empty index", count, err)
+	}
+
+	// The first promoted generation replaces it, and keeps it for rollbacks
+	release := NewRelease(dataDir, "3.6.1")
+	if err := os.MkdirAll(release.Path(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := PromoteRelease(release); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := CurrentRelease(dataDir, "3.6.1"); err != nil || current != release {
+		t.Errorf("CurrentRelease() = %v, %v, want %v", current, err, release.Generation)
+	}
+	if previous, err := PreviousRelease(dataDir, "3.6.1"); err != nil || previous != legacy {
+		t.Errorf("PreviousRelease() = %v, %v, want the legacy release", previous, err)
+	}
+	if err := DeleteRelease(config, legacy); err == nil {
+		t.Error("DeleteRelease() deleted the legacy release")
+	}
+}
@@ -0,0 +1,28 @@
+package database
+
+type Sense struct {
+	PartOfSpeech   []string         `json:"partOfSpeech" bson:"part_of_speech"`
+	AppliesToKanji []string         `json:"appliesToKanji,omitempty" bson:"applies_to_kanji,omitempty"` // Empty when it applies to every kanji
+	AppliesToKana  []string         `json:"appliesToKana,omitempty" bson:"applies_to_kana,omitempty"`   // Empty when it applies to every kana
+	Field          []string         `json:"field,omitempty" bson:"field,omitempty"`                     // Field of application (e.g. 'comp', 'med')
+	Dialect        []string         `json:"dialect,omitempty" bson:"dialect,omitempty"`                 // Regional dialects (e.g. 'ksb')
+	Misc           []string         `json:"misc,omitempty" bson:"misc,omitempty"`                       // Usage notes (e.g. 'uk', 'col')
+	Info           []string         `json:"info,omitempty" bson:"info,omitempty"`                       // Free-text notes
+	LanguageSource []LanguageSource `json:"languageSource,omitempty" bson:"language_source,omitempty"`  // Origin of loanwords
+	Related        []CrossReference `json:"related,omitempty" bson:"related,omitempty"`
+	Antonym        []CrossReference `json:"antonym,omitempty" bson:"antonym,omitempty"`
+	Glosses        []string         `json:"glosses" bson:"glosses"`
+}
+
+type LanguageSource struct {
+	Lang  string `json:"lang" bson:"lang"`                     // ISO 639-2 code of the source language
+	Text  string `json:"text,omitempty" bson:"text,omitempty"` // Original word, when it's known
+	Full  bool   `json:"full" bson:"full"`                     // False when the word is only partially borrowed
+	Wasei bool   `json:"wasei" bson:"wasei"`                   // Japanese-made word using foreign words (e.g. サラリーマン)
+}
+
+type CrossReference struct {
+	Word       string `json:"word" bson:"word"`                                  // Kanji or kana being referenced
+	Reading    string `json:"reading,omitempty" bson:"reading,omitempty"`        // Kana reading, if the reference has one
+	SenseIndex int    `json:"senseIndex,omitempty" bson:"sense_index,omitempty"` // 1-based sense of the referenced word, 0 means any
+}
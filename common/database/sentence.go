@@ -0,0 +1,17 @@
+package database
+
+// Sentence is a Japanese example sentence from Tatoeba, along with its English translations
+type Sentence struct {
+	ID       string   `json:"id" bson:"_id"` // Tatoeba ID of the Japanese sentence
+	Japanese string   `json:"japanese" bson:"japanese"`
+	English  []string `json:"english" bson:"english"`
+	WordIDs  []string `json:"wordIds,omitempty" bson:"word_ids,omitempty"` // JMdict words it's an example of
+}
+
+// SentenceSearchable is the document indexed in the sentences Bleve index
+type SentenceSearchable struct {
+	ID       string   `json:"id"`
+	Japanese string   `json:"japanese"`
+	English  []string `json:"english"`
+	Words    []string `json:"words"` // Linked JMdict word IDs, used to list the examples of a word
+}
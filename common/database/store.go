@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by a Store when the requested document doesn't exist
+var ErrNotFound = errors.New("document not found")
+
+type StorageBackend string
+
+const (
+	MongoBackend StorageBackend = "mongo" // Words live in a MongoDB server
+	BoltBackend  StorageBackend = "bolt"  // Words live in a bbolt file next to the Bleve index
+)
+
+// Store keeps the full documents. Bleve only finds IDs, the Store turns them into words.
+type Store interface {
+	// GetWords returns the words with the given IDs in no particular order, skipping unknown IDs
+	GetWords(ctx context.Context, ids []string) ([]Word, error)
+	// GetWord returns a single word, or ErrNotFound
+	GetWord(ctx context.Context, id string) (*Word, error)
+	PutWords(ctx context.Context, words []Word) error
+
+	// GetKanji returns the kanji with the given literals in no particular order, skipping unknown ones
+	GetKanji(ctx context.Context, literals []string) ([]Kanji, error)
+	// GetKanjiCharacter returns a single kanji, or ErrNotFound
+	GetKanjiCharacter(ctx context.Context, literal string) (*Kanji, error)
+	PutKanji(ctx context.Context, kanji []Kanji) error
+
+	// GetRadicals returns every radical, in no particular order
+	GetRadicals(ctx context.Context) ([]Radical, error)
+	PutRadicals(ctx context.Context, radicals []Radical) error
+
+	// GetNames returns the names with the given IDs in no particular order, skipping unknown IDs
+	GetNames(ctx context.Context, ids []string) ([]Name, error)
+	PutNames(ctx context.Context, names []Name) error
+
+	// GetSentences returns the sentences with the given IDs in no particular order, skipping unknown IDs
+	GetSentences(ctx context.Context, ids []string) ([]Sentence, error)
+	PutSentences(ctx context.Context, sentences []Sentence) error
+
+	GetTags(ctx context.Context) ([]Tag, error)
+	PutTags(ctx context.Context, tags []Tag) error
+
+	// Drop removes every document, used before importing a dictionary again
+	Drop(ctx context.Context) error
+	// Ping checks the store can still be reached
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
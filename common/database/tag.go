@@ -0,0 +1,6 @@
+package database
+
+type Tag struct {
+	Name        string `json:"name" bson:"_id"`                // Short code used by JMdict (e.g. 'v5k')
+	Description string `json:"description" bson:"description"` // Human readable description
+}
@@ -1,14 +1,18 @@
 package database
 
+import "github.com/izquiratops/tango/common/furigana"
+
 type Word struct {
 	ID         string     `json:"id" bson:"_id"`
 	MainWord   Furigana   `json:"mainWord" bson:"main_word"`     // Primary word representation
 	OtherForms []Furigana `json:"otherForms" bson:"other_forms"` // Alternative forms of the word
 	Common     bool       `json:"isCommon" bson:"is_common"`     // Indicates if word is frequently used
 	Meanings   []string   `json:"meanings" bson:"meanings"`      // Word definitions/translations
+	Senses     []Sense    `json:"senses" bson:"senses"`          // Full sense data, one per meaning
 }
 
 type Furigana struct {
-	Word    string `json:"word" bson:"word"`       // Kanji representation (or kana if no kanji exists)
-	Reading string `json:"reading" bson:"reading"` // Kana reading (empty for kana-only words)
+	Word     string          `json:"word" bson:"word"`                             // Kanji representation (or kana if no kanji exists)
+	Reading  string          `json:"reading" bson:"reading"`                       // Kana reading (empty for kana-only words)
+	Segments []furigana.Span `json:"segments,omitempty" bson:"segments,omitempty"` // Reading of every kanji, empty when it couldn't be aligned
 }
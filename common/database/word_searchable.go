@@ -14,6 +14,7 @@ type WordSearchable struct {
 	KanaChar   []string `json:"kana_char"`
 	Meanings   []string `json:"meanings"`
 	Romaji     []string `json:"romaji"`
+	Common     bool     `json:"common"`
 }
 
 func (be *WordSearchable) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,105 @@
+package deinflect
+
+import (
+	"strings"
+)
+
+// WordType is a bitmask with the kind of words a form can be
+type WordType uint16
+
+const (
+	V1   WordType = 1 << iota // Ichidan verb
+	V5                        // Godan verb
+	VS                        // する verb, or a noun taking する
+	VK                        // 来る
+	AdjI                      // い-adjective
+	Masu                      // Intermediate polite form (ます), never a dictionary form
+	Te                        // Intermediate te-form (て/で), never a dictionary form
+)
+
+// DictionaryTypes are the types a dictionary form can have
+const DictionaryTypes = V1 | V5 | VS | VK | AdjI
+
+// Keeps long inputs from exploding into too many candidates
+const maxCandidates = 500
+
+type Candidate struct {
+	Term    string   // Possible dictionary form
+	Types   WordType // What the term must be for the deinflection to be valid
+	Reasons []string // Applied inflections, from the dictionary form outwards (e.g. "polite", "past")
+}
+
+// Reason joins the reasons of a candidate as they're usually written (e.g. "polite past")
+func (c Candidate) Reason() string {
+	return strings.Join(c.Reasons, " ")
+}
+
+// Deinflect returns every dictionary form the text could be an inflection of.
+// Candidates still have to be checked against a dictionary, most of them aren't real words.
+func Deinflect(text string) []Candidate {
+	type key struct {
+		term  string
+		types WordType
+	}
+
+	seen := map[key]bool{{text, 0}: true}
+	queue := []Candidate{{Term: text}}
+	var candidates []Candidate
+
+	for i := 0; i < len(queue) && len(candidates) < maxCandidates; i++ {
+		current := queue[i]
+
+		for _, r := range rules {
+			if !strings.HasSuffix(current.Term, r.kanaIn) {
+				continue
+			}
+
+			// Zero means it's the text as written, which can be anything
+			if current.Types != 0 && current.Types&r.typesIn == 0 {
+				continue
+			}
+
+			term := strings.TrimSuffix(current.Term, r.kanaIn) + r.kanaOut
+			if term == "" || seen[key{term, r.typesOut}] {
+				continue
+			}
+			seen[key{term, r.typesOut}] = true
+
+			reasons := current.Reasons
+			if r.reason != "" {
+				reasons = append([]string{r.reason}, current.Reasons...)
+			}
+
+			next := Candidate{
+				Term:    term,
+				Types:   r.typesOut,
+				Reasons: reasons,
+			}
+
+			queue = append(queue, next)
+			if next.Types&DictionaryTypes != 0 {
+				candidates = append(candidates, next)
+			}
+		}
+	}
+
+	return candidates
+}
+
+// MatchesPartOfSpeech reports if a JMdict part of speech tag (e.g. 'v5k') fits the word types
+func (t WordType) MatchesPartOfSpeech(pos string) bool {
+	switch {
+	case t&V1 != 0 && (pos == "v1" || pos == "v1-s"):
+		return true
+	case t&V5 != 0 && strings.HasPrefix(pos, "v5"):
+		return true
+	case t&VS != 0 && strings.HasPrefix(pos, "vs"):
+		return true
+	case t&VK != 0 && pos == "vk":
+		return true
+	case t&AdjI != 0 && (pos == "adj-i" || pos == "adj-ix"):
+		return true
+	}
+
+	return false
+}
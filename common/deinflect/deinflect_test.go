@@ -0,0 +1,71 @@
+package deinflect
+
+import "testing"
+
+func TestDeinflect(t *testing.T) {
+	testCases := []struct {
+		text   string
+		term   string
+		pos    string
+		reason string
+	}{
+		{"食べました", "食べる", "v1", "polite past"},
+		{"食べない", "食べる", "v1", "negative"},
+		{"食べている", "食べる", "v1", "te-form progressive"},
+		{"食べられる", "食べる", "v1", "potential or passive"},
+		{"行かなかった", "行く", "v5k-s", "negative past"},
+		{"行って", "行く", "v5k-s", "te-form"},
+		{"書きます", "書く", "v5k", "polite"},
+		{"飲みたい", "飲む", "v5m", "desire"},
+		{"読もう", "読む", "v5m", "volitional"},
+		{"話させる", "話す", "v5s", "causative"},
+		{"泳げる", "泳ぐ", "v5g", "potential"},
+		{"死んだ", "死ぬ", "v5n", "past"},
+		{"高くて", "高い", "adj-i", "te-form"},
+		{"高くなかった", "高い", "adj-i", "negative past"},
+		{"勉強しました", "勉強", "vs", "polite past"},
+		{"来ない", "来る", "vk", "negative"},
+		{"こられる", "くる", "vk", "potential or passive"},
+		{"待ってしまった", "待つ", "v5t", "te-form completed past"},
+	}
+
+	for _, testCase := range testCases {
+		found := false
+		for _, candidate := range Deinflect(testCase.text) {
+			if candidate.Term == testCase.term && candidate.Types.MatchesPartOfSpeech(testCase.pos) {
+				found = true
+
+				if candidate.Reason() != testCase.reason {
+					t.Errorf("Deinflect(%q) reason = %q. Expected: %q", testCase.text, candidate.Reason(), testCase.reason)
+				}
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("Deinflect(%q) didn't return %q (%v)", testCase.text, testCase.term, testCase.pos)
+		}
+	}
+}
+
+func TestDeinflectSkipsDictionaryForm(t *testing.T) {
+	for _, candidate := range Deinflect("食べる") {
+		if candidate.Term == "食べる" {
+			t.Errorf("Deinflect() returned the text as written: %+v", candidate)
+		}
+	}
+}
+
+func TestMatchesPartOfSpeech(t *testing.T) {
+	if !V5.MatchesPartOfSpeech("v5k-s") {
+		t.Errorf("V5 should match v5k-s")
+	}
+
+	if V1.MatchesPartOfSpeech("v5r") {
+		t.Errorf("V1 shouldn't match v5r")
+	}
+
+	if (Masu | Te).MatchesPartOfSpeech("v1") {
+		t.Errorf("Intermediate types shouldn't match any part of speech")
+	}
+}
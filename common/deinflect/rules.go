@@ -0,0 +1,165 @@
+package deinflect
+
+type rule struct {
+	kanaIn   string   // Suffix of the inflected form
+	kanaOut  string   // Suffix that replaces it
+	typesIn  WordType // Types the inflected form must allow, zero means only the text as written
+	typesOut WordType // Type of the resulting form
+	reason   string   // Empty for steps that aren't worth showing
+}
+
+// godanRow lists the endings of a godan verb for each inflection stem
+type godanRow struct {
+	u, a, i, e, o string
+	te, ta        string
+}
+
+var godanRows = []godanRow{
+	{u: "う", a: "わ", i: "い", e: "え", o: "お", te: "って", ta: "った"},
+	{u: "く", a: "か", i: "き", e: "け", o: "こ", te: "いて", ta: "いた"},
+	{u: "ぐ", a: "が", i: "ぎ", e: "げ", o: "ご", te: "いで", ta: "いだ"},
+	{u: "す", a: "さ", i: "し", e: "せ", o: "そ", te: "して", ta: "した"},
+	{u: "つ", a: "た", i: "ち", e: "て", o: "と", te: "って", ta: "った"},
+	{u: "ぬ", a: "な", i: "に", e: "ね", o: "の", te: "んで", ta: "んだ"},
+	{u: "ぶ", a: "ば", i: "び", e: "べ", o: "ぼ", te: "んで", ta: "んだ"},
+	{u: "む", a: "ま", i: "み", e: "め", o: "も", te: "んで", ta: "んだ"},
+	{u: "る", a: "ら", i: "り", e: "れ", o: "ろ", te: "って", ta: "った"},
+}
+
+var rules = buildRules()
+
+func buildRules() []rule {
+	var all []rule
+
+	// Auxiliary endings shared by every verb, they turn into an intermediate form
+	all = append(all,
+		rule{"ました", "ます", 0, Masu, "past"},
+		rule{"ません", "ます", 0, Masu, "negative"},
+		rule{"ませんでした", "ます", 0, Masu, "negative past"},
+		rule{"ましょう", "ます", 0, Masu, "volitional"},
+		rule{"まして", "ます", 0, Masu, "te-form"},
+
+		rule{"ている", "て", V1, Te, "progressive"},
+		rule{"てる", "て", V1, Te, "progressive"},
+		rule{"でいる", "で", V1, Te, "progressive"},
+		rule{"でる", "で", V1, Te, "progressive"},
+		rule{"てしまう", "て", V5, Te, "completed"},
+		rule{"でしまう", "で", V5, Te, "completed"},
+		rule{"ちゃう", "て", V5, Te, "completed"},
+		rule{"じゃう", "で", V5, Te, "completed"},
+		rule{"ておく", "て", V5, Te, "in advance"},
+		rule{"でおく", "で", V5, Te, "in advance"},
+		rule{"とく", "て", V5, Te, "in advance"},
+		rule{"どく", "で", V5, Te, "in advance"},
+		rule{"てある", "て", V5, Te, "resultative"},
+		rule{"である", "で", V5, Te, "resultative"},
+		rule{"てください", "て", 0, Te, "request"},
+		rule{"でください", "で", 0, Te, "request"},
+	)
+
+	// Ichidan verbs
+	all = append(all,
+		rule{"ない", "る", AdjI, V1, "negative"},
+		rule{"ず", "る", 0, V1, "negative (zu)"},
+		rule{"ます", "る", Masu, V1, "polite"},
+		rule{"て", "る", Te, V1, "te-form"},
+		rule{"た", "る", 0, V1, "past"},
+		rule{"たら", "る", 0, V1, "conditional (tara)"},
+		rule{"たり", "る", 0, V1, "tari"},
+		rule{"れば", "る", 0, V1, "conditional (ba)"},
+		rule{"よう", "る", 0, V1, "volitional"},
+		rule{"ろ", "る", 0, V1, "imperative"},
+		rule{"たい", "る", AdjI, V1, "desire"},
+		rule{"ながら", "る", 0, V1, "while"},
+		rule{"られる", "る", V1, V1, "potential or passive"},
+		rule{"れる", "る", V1, V1, "potential (colloquial)"},
+		rule{"させる", "る", V1, V1, "causative"},
+	)
+
+	// Godan verbs, one set of rules for every ending
+	for _, row := range godanRows {
+		all = append(all,
+			rule{row.a + "ない", row.u, AdjI, V5, "negative"},
+			rule{row.a + "ず", row.u, 0, V5, "negative (zu)"},
+			rule{row.i + "ます", row.u, Masu, V5, "polite"},
+			rule{row.te, row.u, Te, V5, "te-form"},
+			rule{row.ta, row.u, 0, V5, "past"},
+			rule{row.ta + "ら", row.u, 0, V5, "conditional (tara)"},
+			rule{row.ta + "り", row.u, 0, V5, "tari"},
+			rule{row.e + "ば", row.u, 0, V5, "conditional (ba)"},
+			rule{row.o + "う", row.u, 0, V5, "volitional"},
+			rule{row.e, row.u, 0, V5, "imperative"},
+			rule{row.i + "たい", row.u, AdjI, V5, "desire"},
+			rule{row.i + "ながら", row.u, 0, V5, "while"},
+			rule{row.e + "る", row.u, V1, V5, "potential"},
+			rule{row.a + "れる", row.u, V1, V5, "passive"},
+			rule{row.a + "せる", row.u, V1, V5, "causative"},
+		)
+	}
+
+	// The te-form of 行く is irregular
+	all = append(all,
+		rule{"行って", "行く", Te, V5, "te-form"},
+		rule{"行った", "行く", 0, V5, "past"},
+		rule{"いって", "いく", Te, V5, "te-form"},
+		rule{"いった", "いく", 0, V5, "past"},
+	)
+
+	// する, and nouns that take it
+	all = append(all,
+		rule{"しない", "する", AdjI, VS, "negative"},
+		rule{"せず", "する", 0, VS, "negative (zu)"},
+		rule{"します", "する", Masu, VS, "polite"},
+		rule{"して", "する", Te, VS, "te-form"},
+		rule{"した", "する", 0, VS, "past"},
+		rule{"したら", "する", 0, VS, "conditional (tara)"},
+		rule{"したり", "する", 0, VS, "tari"},
+		rule{"すれば", "する", 0, VS, "conditional (ba)"},
+		rule{"しよう", "する", 0, VS, "volitional"},
+		rule{"しろ", "する", 0, VS, "imperative"},
+		rule{"せよ", "する", 0, VS, "imperative"},
+		rule{"したい", "する", AdjI, VS, "desire"},
+		rule{"しながら", "する", 0, VS, "while"},
+		rule{"できる", "する", V1, VS, "potential"},
+		rule{"される", "する", V1, VS, "passive"},
+		rule{"させる", "する", V1, VS, "causative"},
+		// Nouns like 勉強 are stored without する
+		rule{"する", "", VS, VS, ""},
+	)
+
+	// 来る, written both in kanji and kana
+	for _, ku := range []struct{ ku, ki, ko string }{{"来", "来", "来"}, {"く", "き", "こ"}} {
+		base := ku.ku + "る"
+		all = append(all,
+			rule{ku.ko + "ない", base, AdjI, VK, "negative"},
+			rule{ku.ko + "ず", base, 0, VK, "negative (zu)"},
+			rule{ku.ki + "ます", base, Masu, VK, "polite"},
+			rule{ku.ki + "て", base, Te, VK, "te-form"},
+			rule{ku.ki + "た", base, 0, VK, "past"},
+			rule{ku.ki + "たら", base, 0, VK, "conditional (tara)"},
+			rule{ku.ki + "たり", base, 0, VK, "tari"},
+			rule{ku.ku + "れば", base, 0, VK, "conditional (ba)"},
+			rule{ku.ko + "よう", base, 0, VK, "volitional"},
+			rule{ku.ko + "い", base, 0, VK, "imperative"},
+			rule{ku.ki + "たい", base, AdjI, VK, "desire"},
+			rule{ku.ko + "られる", base, V1, VK, "potential or passive"},
+			rule{ku.ko + "れる", base, V1, VK, "potential (colloquial)"},
+			rule{ku.ko + "させる", base, V1, VK, "causative"},
+		)
+	}
+
+	// い-adjectives, and anything conjugating like them (ない, たい)
+	all = append(all,
+		rule{"くない", "い", AdjI, AdjI, "negative"},
+		rule{"かった", "い", 0, AdjI, "past"},
+		rule{"かったら", "い", 0, AdjI, "conditional (tara)"},
+		rule{"くて", "い", Te, AdjI, "te-form"},
+		rule{"く", "い", 0, AdjI, "adverbial"},
+		rule{"ければ", "い", 0, AdjI, "conditional (ba)"},
+		rule{"さ", "い", 0, AdjI, "noun"},
+		rule{"すぎる", "い", V1, AdjI, "excess"},
+		rule{"そう", "い", 0, AdjI, "seemingness"},
+	)
+
+	return all
+}
@@ -0,0 +1,262 @@
+package furigana
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/izquiratops/tango/common/kana"
+)
+
+// Readings are the ways every kanji can be read, in hiragana (e.g. Kanjidic2 on, kun and nanori readings)
+type Readings map[rune][]string
+
+// Add stores a reading of the kanji. Kun readings like 'と.る' or '-ぶ' are also added as the
+// kanji alone ('と') and as written in compounds without okurigana ('とり', like in 取扱).
+func (r Readings) Add(kanji rune, reading string) {
+	reading = kana.ToHiraganaFromKatakana(strings.ReplaceAll(reading, "-", ""))
+	if reading == "" {
+		return
+	}
+
+	stem, okurigana, found := strings.Cut(reading, ".")
+	r.add(kanji, stem)
+	if !found {
+		return
+	}
+
+	full := stem + okurigana
+	r.add(kanji, full)
+	r.add(kanji, continuativeForm(full))
+}
+
+func (r Readings) add(kanji rune, reading string) {
+	for _, existing := range r[kanji] {
+		if existing == reading {
+			return
+		}
+	}
+
+	r[kanji] = append(r[kanji], reading)
+}
+
+// Align splits a form into the pieces each part of the reading belongs to, so every kanji gets
+// its own furigana: 取り扱い (とりあつかい) → 取(と) り 扱(あつか) い. Kana are read as written and
+// anchor the alignment, the reading left between them belongs to the kanji. Runs of several kanji
+// are split with the given readings when they fit, otherwise they keep the reading of the whole run.
+// It returns nil when the reading doesn't fit the form at all, or fits it in more than one way.
+func Align(word string, reading string, readings Readings) []Span {
+	blocks := splitBlocks(word)
+	if len(blocks) == 0 || reading == "" {
+		return nil
+	}
+
+	var solutions [][]Span
+	alignBlocks(blocks, []rune(reading), nil, &solutions)
+
+	// Ambiguous alignments like 日日 (ひび) can only be settled by the kanji readings
+	if len(solutions) > 1 && readings != nil {
+		var fitting [][]Span
+		for _, solution := range solutions {
+			if fitsReadings(solution, readings) {
+				fitting = append(fitting, solution)
+			}
+		}
+		solutions = fitting
+	}
+
+	if len(solutions) != 1 {
+		return nil
+	}
+
+	var spans []Span
+	for _, span := range solutions[0] {
+		if span.Reading == "" || readings == nil {
+			spans = append(spans, span)
+			continue
+		}
+
+		if perKanji := splitKanji(span, readings); perKanji != nil {
+			spans = append(spans, perKanji...)
+		} else {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+type block struct {
+	text []rune
+	kana bool
+}
+
+// splitBlocks groups the form into runs of kana and runs of anything else (kanji, 々, latin letters...)
+func splitBlocks(word string) []block {
+	var blocks []block
+
+	for _, r := range word {
+		isKana := isKana(r)
+		if last := len(blocks) - 1; last >= 0 && blocks[last].kana == isKana {
+			blocks[last].text = append(blocks[last].text, r)
+			continue
+		}
+
+		blocks = append(blocks, block{text: []rune{r}, kana: isKana})
+	}
+
+	return blocks
+}
+
+// alignBlocks tries every way of giving the reading to the blocks, collecting up to two solutions
+func alignBlocks(blocks []block, reading []rune, spans []Span, solutions *[][]Span) {
+	if len(*solutions) > 1 {
+		return
+	}
+
+	if len(blocks) == 0 {
+		if len(reading) == 0 {
+			*solutions = append(*solutions, append([]Span(nil), spans...))
+		}
+		return
+	}
+
+	current := blocks[0]
+	if current.kana {
+		if len(reading) < len(current.text) || !sameKana(current.text, reading[:len(current.text)]) {
+			return
+		}
+
+		alignBlocks(blocks[1:], reading[len(current.text):], append(spans, Span{Text: string(current.text)}), solutions)
+		return
+	}
+
+	// Every character is read with at least one kana
+	for end := len(current.text); end <= len(reading); end++ {
+		span := Span{Text: string(current.text), Reading: string(reading[:end])}
+		alignBlocks(blocks[1:], reading[end:], append(spans, span), solutions)
+	}
+}
+
+func fitsReadings(spans []Span, readings Readings) bool {
+	for _, span := range spans {
+		if span.Reading != "" && splitKanji(span, readings) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitKanji gives every kanji of the span its part of the reading, or returns nil when the readings don't fit
+func splitKanji(span Span, readings Readings) []Span {
+	text := []rune(span.Text)
+
+	var split func(i int, reading string) []Span
+	split = func(i int, reading string) []Span {
+		if i == len(text) {
+			if reading == "" {
+				return []Span{}
+			}
+			return nil
+		}
+
+		for _, candidate := range kanjiReadings(text, i, readings) {
+			if !strings.HasPrefix(reading, candidate) {
+				continue
+			}
+
+			if rest := split(i+1, strings.TrimPrefix(reading, candidate)); rest != nil {
+				return append([]Span{{Text: string(text[i]), Reading: candidate}}, rest...)
+			}
+		}
+
+		return nil
+	}
+
+	return split(0, span.Reading)
+}
+
+// kanjiReadings lists how the kanji at i can be read, with the sound changes of compounds:
+// rendaku (か → が) and gemination (つ → っ)
+func kanjiReadings(text []rune, i int, readings Readings) []string {
+	r := text[i]
+	// 々 repeats the previous kanji
+	if r == '々' && i > 0 {
+		r = text[i-1]
+	}
+
+	var candidates []string
+	for _, reading := range readings[r] {
+		candidates = append(candidates, reading)
+
+		if i > 0 {
+			candidates = append(candidates, voicedForms(reading)...)
+		}
+		if i < len(text)-1 {
+			if geminated, ok := geminate(reading); ok {
+				candidates = append(candidates, geminated)
+			}
+		}
+	}
+
+	return candidates
+}
+
+var (
+	// Rendaku: the first kana of a compound's second part is often voiced
+	voiced = map[rune][]rune{
+		'か': {'が'}, 'き': {'ぎ'}, 'く': {'ぐ'}, 'け': {'げ'}, 'こ': {'ご'},
+		'さ': {'ざ'}, 'し': {'じ'}, 'す': {'ず'}, 'せ': {'ぜ'}, 'そ': {'ぞ'},
+		'た': {'だ'}, 'ち': {'ぢ', 'じ'}, 'つ': {'づ', 'ず'}, 'て': {'で'}, 'と': {'ど'},
+		'は': {'ば', 'ぱ'}, 'ひ': {'び', 'ぴ'}, 'ふ': {'ぶ', 'ぷ'}, 'へ': {'べ', 'ぺ'}, 'ほ': {'ぼ', 'ぽ'},
+	}
+	// Verbs written in compounds drop their okurigana in the continuative form: と.る → 取(とり)
+	continuative = map[rune]rune{
+		'う': 'い', 'く': 'き', 'ぐ': 'ぎ', 'す': 'し', 'つ': 'ち',
+		'ぬ': 'に', 'ぶ': 'び', 'む': 'み', 'る': 'り',
+	}
+)
+
+func voicedForms(reading string) []string {
+	runes := []rune(reading)
+
+	var forms []string
+	for _, v := range voiced[runes[0]] {
+		forms = append(forms, string(v)+string(runes[1:]))
+	}
+
+	return forms
+}
+
+// geminate turns the last kana into a small っ, as in 学校 (がく → がっ)
+func geminate(reading string) (string, bool) {
+	runes := []rune(reading)
+	if len(runes) < 2 {
+		return "", false
+	}
+
+	switch runes[len(runes)-1] {
+	case 'つ', 'ち', 'く', 'き':
+		return string(runes[:len(runes)-1]) + "っ", true
+	}
+
+	return "", false
+}
+
+func continuativeForm(reading string) string {
+	runes := []rune(reading)
+	if i, ok := continuative[runes[len(runes)-1]]; ok {
+		runes[len(runes)-1] = i
+	}
+
+	return string(runes)
+}
+
+func sameKana(a []rune, b []rune) bool {
+	return kana.ToHiraganaFromKatakana(string(a)) == kana.ToHiraganaFromKatakana(string(b))
+}
+
+func isKana(r rune) bool {
+	// 'ー' is the katakana long vowel mark, it's in neither script
+	return unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
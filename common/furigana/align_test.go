@@ -0,0 +1,68 @@
+package furigana
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testReadings() Readings {
+	readings := Readings{}
+	for kanji, list := range map[rune][]string{
+		'取': {"しゅ", "と.る"},
+		'扱': {"あつか.う"},
+		'学': {"がく", "まな.ぶ"},
+		'校': {"こう"},
+		'食': {"しょく", "た.べる"},
+		'日': {"にち", "ひ", "-び", "か"},
+		'人': {"じん", "にん", "ひと"},
+		'本': {"ほん", "もと"},
+	} {
+		for _, reading := range list {
+			readings.Add(kanji, reading)
+		}
+	}
+	return readings
+}
+
+func TestAlign(t *testing.T) {
+	readings := testReadings()
+
+	tests := []struct {
+		word     string
+		reading  string
+		expected []Span
+	}{
+		{"取り扱い", "とりあつかい", []Span{{"取", "と"}, {"り", ""}, {"扱", "あつか"}, {"い", ""}}},
+		{"学校", "がっこう", []Span{{"学", "がっ"}, {"校", "こう"}}},
+		{"食べる", "たべる", []Span{{"食", "た"}, {"べる", ""}}},
+		{"取扱", "とりあつかい", []Span{{"取", "とり"}, {"扱", "あつかい"}}},
+		{"日々", "ひび", []Span{{"日", "ひ"}, {"々", "び"}}},
+		{"本人", "ほんにん", []Span{{"本", "ほん"}, {"人", "にん"}}},
+		// The kanji readings don't fit, so the run keeps the whole reading
+		{"今日", "きょう", []Span{{"今日", "きょう"}}},
+		// Katakana in the form matches hiragana in the reading
+		{"ヶ月", "かげつ", nil},
+		// Nothing fits the kana
+		{"食べる", "たのむ", nil},
+		{"", "", nil},
+	}
+
+	for _, test := range tests {
+		if got := Align(test.word, test.reading, readings); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Align(%q, %q) = %v, want %v", test.word, test.reading, got, test.expected)
+		}
+	}
+}
+
+func TestAlignWithoutReadings(t *testing.T) {
+	expected := []Span{{"取", "と"}, {"り", ""}, {"扱", "あつか"}, {"い", ""}}
+	if got := Align("取り扱い", "とりあつかい", nil); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Align() = %v, want %v", got, expected)
+	}
+
+	// Without readings a run of kanji can't be split
+	expected = []Span{{"学校", "がっこう"}}
+	if got := Align("学校", "がっこう", nil); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Align() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,83 @@
+package furigana
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Format is how annotated text is written out
+type Format string
+
+const (
+	HTMLFormat Format = "html" // <ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp></ruby>
+	AnkiFormat Format = "anki" // 漢字[かんじ], the syntax of Anki's furigana filter
+	JSONFormat Format = "json" // The spans themselves
+)
+
+// ParseFormat reads a format name, an empty name means HTML
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case "":
+		return HTMLFormat, nil
+	case HTMLFormat, AnkiFormat, JSONFormat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, expected html, anki or json", name)
+	}
+}
+
+// Span is a piece of text and the reading written above it
+type Span struct {
+	Text    string `json:"text"`
+	Reading string `json:"reading,omitempty"` // Empty for text shown as is
+}
+
+// Append adds a span, joining text without reading to the previous span when it has none either
+func Append(spans []Span, span Span) []Span {
+	if last := len(spans) - 1; last >= 0 && span.Reading == "" && spans[last].Reading == "" {
+		spans[last].Text += span.Text
+		return spans
+	}
+
+	return append(spans, span)
+}
+
+// HTML writes the spans with ruby markup, the <rp> fallbacks show the reading in parentheses
+// on browsers without ruby support
+func HTML(spans []Span) string {
+	var b strings.Builder
+
+	for _, span := range spans {
+		if span.Reading == "" {
+			b.WriteString(html.EscapeString(span.Text))
+			continue
+		}
+
+		fmt.Fprintf(&b, "<ruby>%s<rp>(</rp><rt>%s</rt><rp>)</rp></ruby>", html.EscapeString(span.Text), html.EscapeString(span.Reading))
+	}
+
+	return b.String()
+}
+
+// Anki writes the spans in the bracket syntax of Anki's furigana filter. The reading applies to the
+// text after the last space or reading, so a space is put before every annotated span that follows
+// plain text (Anki doesn't show it).
+func Anki(spans []Span) string {
+	var b strings.Builder
+
+	for i, span := range spans {
+		if span.Reading == "" {
+			b.WriteString(span.Text)
+			continue
+		}
+
+		afterReading := i > 0 && spans[i-1].Reading != ""
+		if written := b.String(); written != "" && !afterReading && !strings.HasSuffix(written, " ") && !strings.HasSuffix(written, "\n") {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "%s[%s]", span.Text, span.Reading)
+	}
+
+	return b.String()
+}
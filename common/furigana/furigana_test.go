@@ -0,0 +1,79 @@
+package furigana
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testSpans = []Span{
+	{Text: "私", Reading: "わたし"},
+	{Text: "は"},
+	{Text: "学校", Reading: "がっこう"},
+	{Text: "に<行く>"},
+}
+
+func TestHTML(t *testing.T) {
+	expected := "<ruby>私<rp>(</rp><rt>わたし</rt><rp>)</rp></ruby>は" +
+		"<ruby>学校<rp>(</rp><rt>がっこう</rt><rp>)</rp></ruby>に&lt;行く&gt;"
+
+	if got := HTML(testSpans); got != expected {
+		t.Errorf("HTML() = %q, want %q", got, expected)
+	}
+}
+
+func TestAnki(t *testing.T) {
+	expected := "私[わたし]は 学校[がっこう]に<行く>"
+
+	if got := Anki(testSpans); got != expected {
+		t.Errorf("Anki() = %q, want %q", got, expected)
+	}
+}
+
+func TestAnkiAfterLineBreak(t *testing.T) {
+	spans := []Span{{Text: "猫", Reading: "ねこ"}, {Text: "。\n"}, {Text: "犬", Reading: "いぬ"}}
+
+	if got, expected := Anki(spans), "猫[ねこ]。\n犬[いぬ]"; got != expected {
+		t.Errorf("Anki() = %q, want %q", got, expected)
+	}
+}
+
+func TestAnkiAlignedKanji(t *testing.T) {
+	spans := []Span{{Text: "で"}, {Text: "勉", Reading: "べん"}, {Text: "強", Reading: "きょう"}}
+
+	if got, expected := Anki(spans), "で 勉[べん]強[きょう]"; got != expected {
+		t.Errorf("Anki() = %q, want %q", got, expected)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	var spans []Span
+	spans = Append(spans, Span{Text: "私", Reading: "わたし"})
+	spans = Append(spans, Span{Text: "は"})
+	spans = Append(spans, Span{Text: "、"})
+	spans = Append(spans, Span{Text: "猫", Reading: "ねこ"})
+
+	expected := []Span{{Text: "私", Reading: "わたし"}, {Text: "は、"}, {Text: "猫", Reading: "ねこ"}}
+	if !reflect.DeepEqual(spans, expected) {
+		t.Errorf("Append() = %v, want %v", spans, expected)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected Format
+		valid    bool
+	}{
+		{"", HTMLFormat, true},
+		{"ANKI", AnkiFormat, true},
+		{"json", JSONFormat, true},
+		{"markdown", "", false},
+	}
+
+	for _, tc := range testCases {
+		format, err := ParseFormat(tc.name)
+		if format != tc.expected || (err == nil) != tc.valid {
+			t.Errorf("ParseFormat(%q) = %q, %v", tc.name, format, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package jmdict
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Decoder reads a jmdict-simplified file one entry at a time, so the whole
+// dictionary never has to fit in memory. M is the metadata header and T the
+// type of the entries in the list.
+type Decoder[M any, T any] struct {
+	decoder  *json.Decoder
+	metadata M
+	done     bool
+}
+
+// NewDecoder reads the header of the file up to the list found under listKey.
+// Every field before the list is decoded into the metadata.
+func NewDecoder[M any, T any](r io.Reader, listKey string) (*Decoder[M, T], error) {
+	d := &Decoder[M, T]{
+		decoder: json.NewDecoder(r),
+	}
+
+	if err := expectDelim(d.decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	header := make(map[string]json.RawMessage)
+	for {
+		if !d.decoder.More() {
+			return nil, fmt.Errorf("list %q not found", listKey)
+		}
+
+		token, err := d.decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("error reading key: %w", err)
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v", token)
+		}
+
+		if key == listKey {
+			break
+		}
+
+		var value json.RawMessage
+		if err := d.decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("error reading %q: %w", key, err)
+		}
+		header[key] = value
+	}
+
+	if err := expectDelim(d.decoder, '['); err != nil {
+		return nil, err
+	}
+
+	// Decoding the collected fields at once reuses the struct tags of M
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(headerBytes, &d.metadata); err != nil {
+		return nil, fmt.Errorf("error decoding metadata: %w", err)
+	}
+
+	return d, nil
+}
+
+// NewJMdictDecoder returns a Decoder for the 'words' of a jmdict-eng file
+func NewJMdictDecoder(r io.Reader) (*Decoder[JMdictDictionaryMetadata, JMdictWord], error) {
+	return NewDecoder[JMdictDictionaryMetadata, JMdictWord](r, "words")
+}
+
+func (d *Decoder[M, T]) Metadata() M {
+	return d.metadata
+}
+
+// Next returns the following entry of the list, or io.EOF once it's over
+func (d *Decoder[M, T]) Next() (T, error) {
+	var entry T
+
+	if d.done {
+		return entry, io.EOF
+	}
+
+	if !d.decoder.More() {
+		d.done = true
+		if err := expectDelim(d.decoder, ']'); err != nil {
+			return entry, err
+		}
+		return entry, io.EOF
+	}
+
+	if err := d.decoder.Decode(&entry); err != nil {
+		return entry, fmt.Errorf("error decoding entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("error reading JSON: %w", err)
+	}
+
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package jmdict
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestJMdictDecoder(t *testing.T) {
+	source := `{
+		"version": "3.6.1",
+		"languages": ["eng"],
+		"dictDate": "2025-01-01",
+		"commonOnly": false,
+		"dictRevisions": ["1.09"],
+		"tags": {"v5k": "Godan verb with 'ku' ending"},
+		"words": [
+			{"id": "1", "kanji": [], "kana": [{"common": true, "text": "パン", "tags": [], "appliesToKanji": ["*"]}], "sense": []},
+			{"id": "2", "kanji": [{"common": true, "text": "書く", "tags": []}], "kana": [], "sense": []}
+		]
+	}`
+
+	decoder, err := NewJMdictDecoder(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("Error reading header: %v", err)
+	}
+
+	metadata := decoder.Metadata()
+	if metadata.Version != "3.6.1" || metadata.DictDate != "2025-01-01" {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+	if metadata.Tags["v5k"] != "Godan verb with 'ku' ending" {
+		t.Errorf("Unexpected tags: %v", metadata.Tags)
+	}
+
+	var ids []string
+	for {
+		word, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error reading word: %v", err)
+		}
+		ids = append(ids, word.ID)
+	}
+
+	if strings.Join(ids, ",") != "1,2" {
+		t.Errorf("Unexpected words: %v", ids)
+	}
+}
+
+func TestJMdictDecoderWithoutWords(t *testing.T) {
+	if _, err := NewJMdictDecoder(strings.NewReader(`{"version": "3.6.1"}`)); err == nil {
+		t.Errorf("Expected an error when the words list is missing")
+	}
+}
+
+func TestKanjidic2Decoder(t *testing.T) {
+	source := `{
+		"version": "3.6.1",
+		"languages": ["en"],
+		"dictDate": "2025-01-01",
+		"fileVersion": 4,
+		"databaseVersion": "2025-01",
+		"characters": [
+			{
+				"literal": "語",
+				"codepoints": [{"type": "ucs", "value": "8a9e"}],
+				"radicals": [{"type": "classical", "value": 149}],
+				"misc": {"grade": 2, "strokeCounts": [14], "variants": [], "frequency": 301, "radicalNames": [], "jlptLevel": 4},
+				"dictionaryReferences": [],
+				"queryCodes": [],
+				"readingMeaning": {
+					"groups": [{
+						"readings": [{"type": "ja_on", "onType": null, "status": null, "value": "ゴ"}],
+						"meanings": [{"lang": "en", "value": "word"}]
+					}],
+					"nanori": []
+				}
+			}
+		]
+	}`
+
+	decoder, err := NewKanjidic2Decoder(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("Error reading header: %v", err)
+	}
+
+	if metadata := decoder.Metadata(); metadata.Version != "3.6.1" || metadata.FileVersion != 4 {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+
+	character, err := decoder.Next()
+	if err != nil {
+		t.Fatalf("Error reading character: %v", err)
+	}
+	if character.Literal != "語" || *character.Misc.Grade != 2 || character.ReadingMeaning.Groups[0].Readings[0].Value != "ゴ" {
+		t.Errorf("Unexpected character: %+v", character)
+	}
+
+	if _, err := decoder.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the last character, got %v", err)
+	}
+}
+
+func TestJMnedictDecoder(t *testing.T) {
+	source := `{
+		"version": "3.6.1",
+		"languages": ["eng"],
+		"dictDate": "2025-01-01",
+		"dictRevisions": [],
+		"tags": {"surname": "family or surname", "place": "place name"},
+		"words": [
+			{
+				"id": "5000000",
+				"kanji": [{"text": "田中", "tags": []}],
+				"kana": [{"text": "たなか", "tags": [], "appliesToKanji": ["*"]}],
+				"translation": [{"type": ["surname", "place"], "related": [], "translation": [{"lang": "eng", "text": "Tanaka"}]}]
+			}
+		]
+	}`
+
+	decoder, err := NewJMnedictDecoder(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("Error reading header: %v", err)
+	}
+
+	if tags := decoder.Metadata().Tags; tags["surname"] != "family or surname" {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	name, err := decoder.Next()
+	if err != nil {
+		t.Fatalf("Error reading name: %v", err)
+	}
+	if name.ID != "5000000" || name.Translation[0].Type[1] != "place" || name.Translation[0].Translation[0].Text != "Tanaka" {
+		t.Errorf("Unexpected name: %+v", name)
+	}
+}
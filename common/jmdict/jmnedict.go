@@ -0,0 +1,53 @@
+package jmdict
+
+import "io"
+
+/*
+ * Implements the same interfaces as jmdict-simplified 💕
+ * https://scriptin.github.io/jmdict-simplified/interfaces/JMnedict.html
+ */
+
+type JMnedictDictionaryMetadata struct {
+	DictionaryMetadata[Language]
+	DictRevisions []string          `json:"dictRevisions"`
+	Tags          map[string]string `json:"tags"`
+}
+
+type JMnedict struct {
+	JMnedictDictionaryMetadata
+	Words []JMnedictWord `json:"words"`
+}
+
+type JMnedictWord struct {
+	ID          string                `json:"id"`
+	Kanji       []JMnedictKanji       `json:"kanji"`
+	Kana        []JMnedictKana        `json:"kana"`
+	Translation []JMnedictTranslation `json:"translation"`
+}
+
+type JMnedictKanji struct {
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+type JMnedictKana struct {
+	Text           string   `json:"text"`
+	Tags           []string `json:"tags"`
+	AppliesToKanji []string `json:"appliesToKanji"`
+}
+
+type JMnedictTranslation struct {
+	Type        []string                         `json:"type"` // Name types, e.g. 'surname', 'place' or 'company'
+	Related     []Xref                           `json:"related"`
+	Translation []JMnedictTranslationTranslation `json:"translation"`
+}
+
+type JMnedictTranslationTranslation struct {
+	Lang Language `json:"lang"`
+	Text string   `json:"text"`
+}
+
+// NewJMnedictDecoder returns a Decoder for the 'words' of a jmnedict-all file
+func NewJMnedictDecoder(r io.Reader) (*Decoder[JMnedictDictionaryMetadata, JMnedictWord], error) {
+	return NewDecoder[JMnedictDictionaryMetadata, JMnedictWord](r, "words")
+}
@@ -0,0 +1,115 @@
+package jmdict
+
+import "io"
+
+/*
+ * Implements the same interfaces as jmdict-simplified 💕
+ * https://scriptin.github.io/jmdict-simplified/interfaces/Kanjidic2.html
+ */
+
+type Kanjidic2DictionaryMetadata struct {
+	DictionaryMetadata[Language]
+	DatabaseVersion string `json:"databaseVersion"`
+	FileVersion     int    `json:"fileVersion"`
+}
+
+type Kanjidic2 struct {
+	Kanjidic2DictionaryMetadata
+	Characters []Kanjidic2Character `json:"characters"`
+}
+
+type Kanjidic2Character struct {
+	Literal              string                         `json:"literal"`
+	Codepoints           []Kanjidic2Codepoint           `json:"codepoints"`
+	Radicals             []Kanjidic2Radical             `json:"radicals"`
+	Misc                 Kanjidic2Misc                  `json:"misc"`
+	DictionaryReferences []Kanjidic2DictionaryReference `json:"dictionaryReferences"`
+	QueryCodes           []Kanjidic2QueryCode           `json:"queryCodes"`
+	ReadingMeaning       *Kanjidic2ReadingMeaning       `json:"readingMeaning"`
+}
+
+type Kanjidic2Codepoint struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type Kanjidic2RadicalType string
+
+const (
+	Classical Kanjidic2RadicalType = "classical"
+	NelsonC   Kanjidic2RadicalType = "nelson_c"
+)
+
+type Kanjidic2Radical struct {
+	Type  Kanjidic2RadicalType `json:"type"`
+	Value int                  `json:"value"`
+}
+
+type Kanjidic2Misc struct {
+	Grade        *int               `json:"grade"`
+	StrokeCounts []int              `json:"strokeCounts"`
+	Variants     []Kanjidic2Variant `json:"variants"`
+	Frequency    *int               `json:"frequency"`
+	RadicalNames []string           `json:"radicalNames"`
+	JLPTLevel    *int               `json:"jlptLevel"`
+}
+
+type Kanjidic2Variant struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type Kanjidic2DictionaryReference struct {
+	Type      string                   `json:"type"`
+	Morohashi *Kanjidic2MorohashiIndex `json:"morohashi"`
+	Value     string                   `json:"value"`
+}
+
+type Kanjidic2MorohashiIndex struct {
+	Volume int `json:"volume"`
+	Page   int `json:"page"`
+}
+
+type Kanjidic2QueryCode struct {
+	Type                  string  `json:"type"`
+	SkipMisclassification *string `json:"skipMisclassification"`
+	Value                 string  `json:"value"`
+}
+
+type Kanjidic2ReadingMeaning struct {
+	Groups []Kanjidic2ReadingMeaningGroup `json:"groups"`
+	Nanori []string                       `json:"nanori"`
+}
+
+type Kanjidic2ReadingMeaningGroup struct {
+	Readings []Kanjidic2Reading `json:"readings"`
+	Meanings []Kanjidic2Meaning `json:"meanings"`
+}
+
+type Kanjidic2ReadingType string
+
+const (
+	Pinyin      Kanjidic2ReadingType = "pinyin"
+	KoreanR     Kanjidic2ReadingType = "korean_r"
+	KoreanH     Kanjidic2ReadingType = "korean_h"
+	Vietnam     Kanjidic2ReadingType = "vietnam"
+	JapaneseOn  Kanjidic2ReadingType = "ja_on"
+	JapaneseKun Kanjidic2ReadingType = "ja_kun"
+)
+
+type Kanjidic2Reading struct {
+	Type   Kanjidic2ReadingType `json:"type"`
+	OnType *string              `json:"onType"`
+	Status *string              `json:"status"`
+	Value  string               `json:"value"`
+}
+
+type Kanjidic2Meaning struct {
+	Lang  Language `json:"lang"`
+	Value string   `json:"value"`
+}
+
+// NewKanjidic2Decoder returns a Decoder for the 'characters' of a kanjidic2-en file
+func NewKanjidic2Decoder(r io.Reader) (*Decoder[Kanjidic2DictionaryMetadata, Kanjidic2Character], error) {
+	return NewDecoder[Kanjidic2DictionaryMetadata, Kanjidic2Character](r, "characters")
+}
@@ -0,0 +1,27 @@
+package jmdict
+
+/*
+ * Implements the same interfaces as jmdict-simplified 💕
+ * https://scriptin.github.io/jmdict-simplified/interfaces/Kradfile.html
+ * https://scriptin.github.io/jmdict-simplified/interfaces/Radkfile.html
+ *
+ * Both files are small maps rather than lists, so they're decoded at once instead of streamed.
+ */
+
+// Kradfile lists the radicals (components) every kanji is made of
+type Kradfile struct {
+	Version string              `json:"version"`
+	Kanji   map[string][]string `json:"kanji"`
+}
+
+// Radkfile lists the kanji containing every radical
+type Radkfile struct {
+	Version  string                         `json:"version"`
+	Radicals map[string]RadkfileRadicalInfo `json:"radicals"`
+}
+
+type RadkfileRadicalInfo struct {
+	StrokeCount int      `json:"strokeCount"`
+	Code        *string  `json:"code"` // JIS X 0212 code for radicals without their own character
+	Kanji       []string `json:"kanji"`
+}
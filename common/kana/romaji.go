@@ -0,0 +1,210 @@
+package kana
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	hiraganaStart = 'ぁ'
+	hiraganaEnd   = 'ゖ'
+	// Distance between a hiragana and its katakana counterpart
+	katakanaOffset = 'ァ' - 'ぁ'
+)
+
+// ToHiragana converts a romaji text into hiragana. It returns false when some
+// part of the text isn't valid romaji, like most English words.
+func ToHiragana(romaji string) (string, bool) {
+	text, ok := normalizeRomaji(romaji)
+	if !ok || text == "" {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		next := byte(0)
+		if i+1 < len(text) {
+			next = text[i+1]
+		}
+
+		switch {
+		case c == '-':
+			b.WriteString("ー")
+			i++
+			continue
+		case c == '\'':
+			// Only meaningful after 'n', which already consumed it
+			i++
+			continue
+		case c == 'n' && next == 'n' && (i+2 == len(text) || !isSyllableStart(text[i+2])):
+			// "nn" typed as a whole syllabic n
+			b.WriteString("ん")
+			i += 2
+			continue
+		case c == 'n' && !isSyllableStart(next):
+			b.WriteString("ん")
+			i++
+			if next == '\'' {
+				i++
+			}
+			continue
+		case c == 'm' && (next == 'b' || next == 'p' || next == 'm'):
+			// Hepburn writes the syllabic n as 'm' before labials (e.g. "shimbun")
+			b.WriteString("ん")
+			i++
+			continue
+		case c == 't' && strings.HasPrefix(text[i+1:], "ch"):
+			// Hepburn doubles "ch" as "tch" (e.g. "matcha")
+			b.WriteString("っ")
+			i++
+			continue
+		case c == next && isConsonant(c):
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		matched := false
+		for length := 3; length >= 1; length-- {
+			if i+length > len(text) {
+				continue
+			}
+
+			if hiragana, ok := romajiToHiragana[text[i:i+length]]; ok {
+				b.WriteString(hiragana)
+				i += length
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return "", false
+		}
+	}
+
+	return b.String(), true
+}
+
+// ToKatakana converts every hiragana in the text to katakana, leaving anything else untouched
+func ToKatakana(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= hiraganaStart && r <= hiraganaEnd {
+			return r + katakanaOffset
+		}
+		return r
+	}, text)
+}
+
+// ToHiraganaFromKatakana converts every katakana in the text to hiragana, leaving anything else untouched
+func ToHiraganaFromKatakana(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= hiraganaStart+katakanaOffset && r <= hiraganaEnd+katakanaOffset {
+			return r - katakanaOffset
+		}
+		return r
+	}, text)
+}
+
+// ToRomaji converts hiragana and katakana into modified Hepburn romaji.
+// Characters that aren't kana are copied as they are.
+func ToRomaji(text string) string {
+	runes := []rune(ToHiraganaFromKatakana(text))
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		syllable, length := nextSyllable(runes[i:])
+		if length > 0 {
+			b.WriteString(syllable)
+			i += length
+			continue
+		}
+
+		switch runes[i] {
+		case 'っ':
+			// Double the consonant of the next syllable
+			if next, length := nextSyllable(runes[i+1:]); length > 0 {
+				if strings.HasPrefix(next, "ch") {
+					b.WriteByte('t')
+				} else if isConsonant(next[0]) {
+					b.WriteByte(next[0])
+				}
+			}
+		case 'ー':
+			// Repeat the previous vowel
+			if current := b.String(); len(current) > 0 && isVowel(current[len(current)-1]) {
+				b.WriteByte(current[len(current)-1])
+			}
+		default:
+			b.WriteRune(runes[i])
+		}
+		i++
+	}
+
+	return b.String()
+}
+
+// NormalizeRomaji returns the romaji text in the same Hepburn spelling used in the index,
+// so "tukue", "tsukue" and "tsúkue" all end up as "tsukue"
+func NormalizeRomaji(romaji string) (string, bool) {
+	hiragana, ok := ToHiragana(romaji)
+	if !ok {
+		return "", false
+	}
+
+	return ToRomaji(hiragana), true
+}
+
+func nextSyllable(runes []rune) (string, int) {
+	if len(runes) >= 2 {
+		if romaji, ok := hiraganaToRomaji[string(runes[:2])]; ok {
+			return romaji, 2
+		}
+	}
+
+	if len(runes) >= 1 {
+		if romaji, ok := hiraganaToRomaji[string(runes[:1])]; ok {
+			return romaji, 1
+		}
+	}
+
+	return "", 0
+}
+
+// normalizeRomaji lowercases the text, expands long vowel marks and drops whitespace.
+// It fails if there's any character that can't be part of romaji.
+func normalizeRomaji(romaji string) (string, bool) {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(romaji) {
+		if expanded, ok := longVowels[r]; ok {
+			b.WriteString(expanded)
+			continue
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r >= 'a' && r <= 'z', r == '-', r == '\'':
+			b.WriteRune(r)
+		default:
+			return "", false
+		}
+	}
+
+	return b.String(), true
+}
+
+func isVowel(c byte) bool {
+	return c == 'a' || c == 'i' || c == 'u' || c == 'e' || c == 'o'
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c)
+}
+
+// isSyllableStart reports if a 'n' followed by c belongs to the next syllable (like "na" or "nya")
+func isSyllableStart(c byte) bool {
+	return isVowel(c) || c == 'y'
+}
@@ -0,0 +1,83 @@
+package kana
+
+import "testing"
+
+func TestToHiragana(t *testing.T) {
+	testCases := []struct {
+		romaji   string
+		expected string
+		ok       bool
+	}{
+		{"taberu", "たべる", true},
+		{"arigatou", "ありがとう", true},
+		{"Arigatō", "ありがとう", true},
+		{"konnichiwa", "こんにちわ", true},
+		{"shinbun", "しんぶん", true},
+		{"shimbun", "しんぶん", true},
+		{"sinbun", "しんぶん", true},
+		{"kin'you", "きんよう", true},
+		{"matcha", "まっちゃ", true},
+		{"kitte", "きって", true},
+		{"tukue", "つくえ", true},
+		{"tsukue", "つくえ", true},
+		{"zyuusyo", "じゅうしょ", true},
+		{"juusho", "じゅうしょ", true},
+		{"hon", "ほん", true},
+		{"kinn", "きん", true},
+		{"ko-hi-", "こーひー", true},
+		{"arigatou gozaimasu", "ありがとうございます", true},
+		{"bread", "", false},
+		{"hello", "", false},
+		{"c3po", "", false},
+		{"", "", false},
+	}
+
+	for _, testCase := range testCases {
+		got, ok := ToHiragana(testCase.romaji)
+		if got != testCase.expected || ok != testCase.ok {
+			t.Errorf("ToHiragana(%q) = %q, %v. Expected: %q, %v", testCase.romaji, got, ok, testCase.expected, testCase.ok)
+		}
+	}
+}
+
+func TestToRomaji(t *testing.T) {
+	testCases := []struct {
+		kana     string
+		expected string
+	}{
+		{"たべる", "taberu"},
+		{"しんぶん", "shinbun"},
+		{"きんようび", "kinyoubi"},
+		{"まっちゃ", "matcha"},
+		{"きって", "kitte"},
+		{"じゅうしょ", "juusho"},
+		{"コーヒー", "koohii"},
+		{"パーティー", "paatii"},
+		{"ファイル", "fairu"},
+		{"ちぢむ", "chijimu"},
+	}
+
+	for _, testCase := range testCases {
+		if got := ToRomaji(testCase.kana); got != testCase.expected {
+			t.Errorf("ToRomaji(%q) = %q. Expected: %q", testCase.kana, got, testCase.expected)
+		}
+	}
+}
+
+func TestNormalizeRomaji(t *testing.T) {
+	for _, romaji := range []string{"tukue", "tsukue", "TSUKUE"} {
+		if got, _ := NormalizeRomaji(romaji); got != "tsukue" {
+			t.Errorf("NormalizeRomaji(%q) = %q. Expected: %q", romaji, got, "tsukue")
+		}
+	}
+}
+
+func TestToKatakana(t *testing.T) {
+	if got := ToKatakana("てれび"); got != "テレビ" {
+		t.Errorf("ToKatakana() = %q. Expected: %q", got, "テレビ")
+	}
+
+	if got := ToHiraganaFromKatakana("テレビ"); got != "てれび" {
+		t.Errorf("ToHiraganaFromKatakana() = %q. Expected: %q", got, "てれび")
+	}
+}
@@ -0,0 +1,109 @@
+package kana
+
+// romajiToHiragana accepts Hepburn, Kunrei-shiki and Nihon-shiki spellings
+var romajiToHiragana = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+
+	"sa": "さ", "shi": "し", "si": "し", "su": "す", "se": "せ", "so": "そ",
+	"sha": "しゃ", "shu": "しゅ", "sho": "しょ", "she": "しぇ",
+	"sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+	"za": "ざ", "ji": "じ", "zi": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"ja": "じゃ", "ju": "じゅ", "jo": "じょ", "je": "じぇ",
+	"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+
+	"ta": "た", "chi": "ち", "ti": "ち", "tsu": "つ", "tu": "つ", "te": "て", "to": "と",
+	"cha": "ちゃ", "chu": "ちゅ", "cho": "ちょ", "che": "ちぇ",
+	"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+	"cya": "ちゃ", "cyu": "ちゅ", "cyo": "ちょ",
+	"tsa": "つぁ",
+	"da":  "だ", "di": "ぢ", "du": "づ", "dzu": "づ", "de": "で", "do": "ど",
+	"dya": "ぢゃ", "dyu": "ぢゅ", "dyo": "ぢょ",
+
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+
+	"ha": "は", "hi": "ひ", "fu": "ふ", "hu": "ふ", "he": "へ", "ho": "ほ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+
+	"wa": "わ", "wi": "うぃ", "we": "うぇ", "wo": "を",
+
+	"va": "ゔぁ", "vi": "ゔぃ", "vu": "ゔ", "ve": "ゔぇ", "vo": "ゔぉ",
+}
+
+// hiraganaToRomaji produces modified Hepburn, the same spelling used to index words
+var hiraganaToRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"つぁ": "tsa", "てぃ": "ti", "とぅ": "tu",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"でぃ": "di", "どぅ": "du",
+
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo",
+
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ゎ": "wa",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+
+	"ゔ": "vu", "ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+
+	"ん": "n",
+}
+
+// Long vowels written with macrons or circumflexes
+var longVowels = map[rune]string{
+	'ā': "aa", 'â': "aa",
+	'ī': "ii", 'î': "ii",
+	'ū': "uu", 'û': "uu",
+	'ē': "ei", 'ê': "ei",
+	'ō': "ou", 'ô': "ou",
+}
@@ -0,0 +1,92 @@
+// Package logging sets up the structured logger of the client, every record logged with a
+// request context carries the ID of that request.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type Format string
+
+const (
+	TextFormat Format = "text" // key=value pairs, easier to read in a terminal
+	JSONFormat Format = "json" // One object per line, for log collectors
+)
+
+// ParseFormat reads the output format, an empty string is text
+func ParseFormat(format string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case "", TextFormat:
+		return TextFormat, nil
+	case JSONFormat:
+		return JSONFormat, nil
+	default:
+		return "", fmt.Errorf("log format must be either 'text' or 'json', got %q", format)
+	}
+}
+
+// ParseLevel reads a level name (debug, info, warn or error), an empty string is info
+func ParseLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("log level must be one of debug, info, warn or error, got %q", level)
+	}
+
+	return parsed, nil
+}
+
+// New returns a logger writing records of the given level or above to w
+func New(w io.Writer, format Format, level slog.Level) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if format == JSONFormat {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	return slog.New(contextHandler{handler})
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of the request being served
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the ID of the request being served, or an empty string outside requests
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID of the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
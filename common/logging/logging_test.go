@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestRequestIDIsLogged(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, JSONFormat, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	logger.With("component", "search").ErrorContext(ctx, "Failed to run Bleve query")
+	logger.DebugContext(ctx, "Below the level")
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", out.String(), err)
+	}
+
+	if record["request_id"] != "abc123" || record["component"] != "search" {
+		t.Errorf("record = %v, want the request ID and the logger attributes", record)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		level    string
+		expected slog.Level
+		valid    bool
+	}{
+		{"", slog.LevelInfo, true},
+		{"debug", slog.LevelDebug, true},
+		{"WARN", slog.LevelWarn, true},
+		{"verbose", 0, false},
+	}
+
+	for _, tc := range testCases {
+		level, err := ParseLevel(tc.level)
+		if (err == nil) != tc.valid || level != tc.expected {
+			t.Errorf("ParseLevel(%q) = %v, %v", tc.level, level, err)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(""); err != nil || format != TextFormat {
+		t.Errorf("ParseFormat(\"\") = %v, %v, want text", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") should fail")
+	}
+}
@@ -0,0 +1,181 @@
+package segment
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/izquiratops/tango/common/deinflect"
+)
+
+const (
+	maxTokenLength = 12 // In runes, longer JMdict forms are rare and only slow the lattice down
+	wordCost       = 1
+	unknownCost    = 3 // Per character, so any split into words beats leaving text unknown
+)
+
+// Dictionary tells which strings are written forms of some word
+type Dictionary interface {
+	Contains(form string) bool
+}
+
+// Forms is a Dictionary kept in memory
+type Forms map[string]struct{}
+
+func (f Forms) Add(form string) {
+	f[form] = struct{}{}
+}
+
+func (f Forms) Contains(form string) bool {
+	_, ok := f[form]
+	return ok
+}
+
+type Token struct {
+	Surface string             // As written in the text
+	Base    string             // Dictionary form, the same as Surface unless it's conjugated
+	Reason  string             // Applied inflections (e.g. "polite past"), empty unless it's conjugated
+	Types   deinflect.WordType // What the base must be for the conjugation to be valid, 0 unless it's conjugated
+	Known   bool               // False for text that isn't in the dictionary, like punctuation or unknown names
+}
+
+// Conjugated tells if the token is an inflection of its base form
+func (t Token) Conjugated() bool {
+	return t.Surface != t.Base
+}
+
+// Segment splits text into dictionary words. Every possible word of the text is a node of a lattice,
+// and the path with the fewest words wins, longer words first on ties. Conjugated words are matched
+// by their dictionary forms, and the characters no word covers are grouped into unknown tokens.
+// Joining every surface gives the text back.
+func Segment(text string, dict Dictionary) []Token {
+	runes := []rune(text)
+
+	type node struct {
+		cost  int
+		token Token
+		next  int
+	}
+
+	// best[i] is the cheapest way to split runes[i:], so it's filled from the end
+	best := make([]node, len(runes)+1)
+	for i := len(runes) - 1; i >= 0; i-- {
+		best[i] = node{
+			cost:  unknownCost + best[i+1].cost,
+			token: Token{Surface: string(runes[i]), Base: string(runes[i])},
+			next:  i + 1,
+		}
+
+		// Longest first, so a tie keeps the longest word
+		for j := min(len(runes), i+maxTokenLength); j > i; j-- {
+			token, ok := match(string(runes[i:j]), dict)
+			if !ok {
+				continue
+			}
+
+			if cost := wordCost + best[j].cost; cost < best[i].cost {
+				best[i] = node{cost: cost, token: token, next: j}
+			}
+		}
+	}
+
+	var tokens []Token
+	for i := 0; i < len(runes); i = best[i].next {
+		token := best[i].token
+
+		if !token.Known {
+			// Runs of unknown characters are kept together, except for punctuation and spaces
+			if last := len(tokens) - 1; last >= 0 && !tokens[last].Known && !isSeparator(tokens[last].Surface) && !isSeparator(token.Surface) {
+				tokens[last].Surface += token.Surface
+				tokens[last].Base = tokens[last].Surface
+				continue
+			}
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+// match tells if the text is a word as written, or a conjugation of one
+func match(text string, dict Dictionary) (Token, bool) {
+	if dict.Contains(text) {
+		return Token{Surface: text, Base: text, Known: true}, true
+	}
+
+	// Conjugated endings are always written in kana
+	if last := []rune(text)[len([]rune(text))-1]; !isKana(last) {
+		return Token{}, false
+	}
+
+	// Candidates come out shortest chain first, so simpler explanations win
+	for _, candidate := range deinflect.Deinflect(text) {
+		if dict.Contains(candidate.Term) {
+			return Token{
+				Surface: text,
+				Base:    candidate.Term,
+				Reason:  candidate.Reason(),
+				Types:   candidate.Types,
+				Known:   true,
+			}, true
+		}
+	}
+
+	return Token{}, false
+}
+
+// InflectReading returns the reading of a conjugated word, given the reading of its dictionary form.
+// The kanji stem is shared, so only the okurigana changes: 食べる (たべる) → 食べました (たべました).
+// It returns "" when the reading can't be told this way.
+func InflectReading(surface string, base string, baseReading string) string {
+	// The stem of 来る is read く, こ or き depending on the ending
+	if strings.HasSuffix(base, "来る") && surface != base {
+		return ""
+	}
+
+	stem := commonPrefix(surface, base)
+	baseEnding := strings.TrimPrefix(base, stem)
+
+	if !strings.HasSuffix(baseReading, baseEnding) {
+		return ""
+	}
+
+	return strings.TrimSuffix(baseReading, baseEnding) + strings.TrimPrefix(surface, stem)
+}
+
+func commonPrefix(a string, b string) string {
+	ra, rb := []rune(a), []rune(b)
+
+	i := 0
+	for i < len(ra) && i < len(rb) && ra[i] == rb[i] {
+		i++
+	}
+
+	return string(ra[:i])
+}
+
+// IsKana tells if the text is only hiragana and katakana, words written this way need no furigana
+func IsKana(text string) bool {
+	for _, r := range text {
+		if !isKana(r) {
+			return false
+		}
+	}
+
+	return text != ""
+}
+
+func isKana(r rune) bool {
+	// 'ー' is the katakana long vowel mark, it's in neither script
+	return unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+func isSeparator(text string) bool {
+	for _, r := range text {
+		if !unicode.IsPunct(r) && !unicode.IsSymbol(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
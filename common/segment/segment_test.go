@@ -0,0 +1,87 @@
+package segment
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testForms() Forms {
+	forms := make(Forms)
+	for _, form := range []string{"私", "は", "パン", "を", "食べる", "学校", "学", "校", "に", "行く", "高い", "今日", "今", "日"} {
+		forms.Add(form)
+	}
+
+	return forms
+}
+
+func surfaces(tokens []Token) []string {
+	var result []string
+	for _, token := range tokens {
+		result = append(result, token.Surface)
+	}
+
+	return result
+}
+
+func TestSegment(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected []string
+	}{
+		{"私はパンを食べる。", []string{"私", "は", "パン", "を", "食べる", "。"}},
+		{"学校に行きました", []string{"学校", "に", "行きました"}},
+		{"今日は高くない", []string{"今日", "は", "高くない"}},
+		{"私はジョンです", []string{"私", "は", "ジョンです"}},
+		{"私 は、パン", []string{"私", " ", "は", "、", "パン"}},
+		{"", nil},
+	}
+
+	for _, tc := range testCases {
+		if got := surfaces(Segment(tc.text, testForms())); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Segment(%q) = %v, want %v", tc.text, got, tc.expected)
+		}
+	}
+}
+
+func TestSegmentConjugations(t *testing.T) {
+	tokens := Segment("学校に行きました", testForms())
+
+	last := tokens[len(tokens)-1]
+	if last.Base != "行く" || last.Reason != "polite past" || !last.Known || !last.Conjugated() {
+		t.Errorf("Unexpected conjugated token: %+v", last)
+	}
+
+	if first := tokens[0]; first.Conjugated() || first.Types != 0 {
+		t.Errorf("Unexpected plain token: %+v", first)
+	}
+}
+
+func TestSegmentUnknown(t *testing.T) {
+	tokens := Segment("私はジョンです", testForms())
+
+	if unknown := tokens[2]; unknown.Known {
+		t.Errorf("Expected %q to be unknown", unknown.Surface)
+	}
+}
+
+func TestInflectReading(t *testing.T) {
+	testCases := []struct {
+		surface     string
+		base        string
+		baseReading string
+		expected    string
+	}{
+		{"食べました", "食べる", "たべる", "たべました"},
+		{"行かなかった", "行く", "いく", "いかなかった"},
+		{"勉強しました", "勉強", "べんきょう", "べんきょうしました"},
+		{"高くない", "高い", "たかい", "たかくない"},
+		{"来ない", "来る", "くる", ""},
+		{"来る", "来る", "くる", "くる"},
+	}
+
+	for _, tc := range testCases {
+		if got := InflectReading(tc.surface, tc.base, tc.baseReading); got != tc.expected {
+			t.Errorf("InflectReading(%q, %q, %q) = %q, want %q", tc.surface, tc.base, tc.baseReading, got, tc.expected)
+		}
+	}
+}
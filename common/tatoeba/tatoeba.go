@@ -0,0 +1,183 @@
+package tatoeba
+
+/*
+ * Reads the Tatoeba exports 📝
+ * https://tatoeba.org/en/downloads
+ *
+ * Sentence pairs, tab separated: Japanese ID, Japanese text, English ID, English text
+ * Japanese indices (jpn_indices.csv), also tab separated: Japanese ID, English ID, headwords
+ *
+ * Headwords are written like the 'B' lines of the Tanaka Corpus, separated by spaces:
+ * 	word(reading)[sense]{form}~
+ * https://www.edrdg.org/wiki/index.php/Tanaka_Corpus#Current_Format_(WWWJDIC)
+ */
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const maxLineSize = 1024 * 1024
+
+// Pair is a Japanese sentence and one of its English translations
+type Pair struct {
+	JapaneseID string
+	Japanese   string
+	EnglishID  string
+	English    string
+}
+
+// Index links a Japanese sentence to the dictionary words used in it
+type Index struct {
+	SentenceID string
+	MeaningID  string // English sentence the index was written for
+	Headwords  []Headword
+}
+
+type Headword struct {
+	Word    string // Dictionary form, as found in JMdict
+	Reading string // Only given when the word alone is ambiguous
+	Sense   int    // 1-based JMdict sense, 0 when not given
+	Form    string // How it's written in the sentence, only given when it differs from Word
+	Checked bool   // Marked with '~', a good and checked example of the word
+}
+
+// reader splits a tab separated file into lines with a fixed amount of fields
+type reader struct {
+	scanner *bufio.Scanner
+	fields  int
+	line    int
+}
+
+func newReader(r io.Reader, fields int) *reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	return &reader{scanner: scanner, fields: fields}
+}
+
+// next returns the fields of the next non empty line, or io.EOF
+func (r *reader) next() ([]string, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := strings.TrimRight(r.scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", r.fields)
+		if len(fields) != r.fields {
+			return nil, fmt.Errorf("line %d has %d fields, expected %d", r.line, len(fields), r.fields)
+		}
+
+		return fields, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+type PairReader struct {
+	reader *reader
+}
+
+func NewPairReader(r io.Reader) *PairReader {
+	return &PairReader{reader: newReader(r, 4)}
+}
+
+// Next returns the next pair, or io.EOF once the file is over
+func (p *PairReader) Next() (Pair, error) {
+	fields, err := p.reader.next()
+	if err != nil {
+		return Pair{}, err
+	}
+
+	return Pair{
+		JapaneseID: fields[0],
+		Japanese:   fields[1],
+		EnglishID:  fields[2],
+		English:    fields[3],
+	}, nil
+}
+
+type IndexReader struct {
+	reader *reader
+}
+
+func NewIndexReader(r io.Reader) *IndexReader {
+	return &IndexReader{reader: newReader(r, 3)}
+}
+
+// Next returns the next index, or io.EOF once the file is over
+func (i *IndexReader) Next() (Index, error) {
+	fields, err := i.reader.next()
+	if err != nil {
+		return Index{}, err
+	}
+
+	return Index{
+		SentenceID: fields[0],
+		MeaningID:  fields[1],
+		Headwords:  ParseHeadwords(fields[2]),
+	}, nil
+}
+
+// ParseHeadwords splits the space separated headwords of an index
+func ParseHeadwords(s string) []Headword {
+	var headwords []Headword
+
+	for _, field := range strings.Fields(s) {
+		if headword := ParseHeadword(field); headword.Word != "" {
+			headwords = append(headwords, headword)
+		}
+	}
+
+	return headwords
+}
+
+// ParseHeadword reads a single 'word(reading)[sense]{form}~', every part but the word is optional
+func ParseHeadword(s string) Headword {
+	end := strings.IndexAny(s, "([{~")
+	if end < 0 {
+		return Headword{Word: s}
+	}
+
+	headword := Headword{Word: s[:end]}
+	rest := s[end:]
+
+	for rest != "" {
+		var value string
+		var found bool
+
+		switch rest[0] {
+		case '(':
+			value, rest, found = strings.Cut(rest[1:], ")")
+			headword.Reading = value
+		case '[':
+			value, rest, found = strings.Cut(rest[1:], "]")
+			headword.Sense, _ = strconv.Atoi(value)
+		case '{':
+			value, rest, found = strings.Cut(rest[1:], "}")
+			headword.Form = value
+		case '~':
+			headword.Checked = true
+			rest, found = rest[1:], true
+		default:
+			// Anything else is malformed, keep what was read so far
+			found = false
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return headword
+}
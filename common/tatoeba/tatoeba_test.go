@@ -0,0 +1,90 @@
+package tatoeba
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPairReader(t *testing.T) {
+	source := "74172\tパンを食べる。\t1276\tI eat bread.\r\n" +
+		"\n" +
+		"74172\tパンを食べる。\t5012\tI'm eating bread.\n"
+
+	reader := NewPairReader(strings.NewReader(source))
+
+	var pairs []Pair
+	for {
+		pair, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error reading pair: %v", err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	expected := []Pair{
+		{JapaneseID: "74172", Japanese: "パンを食べる。", EnglishID: "1276", English: "I eat bread."},
+		{JapaneseID: "74172", Japanese: "パンを食べる。", EnglishID: "5012", English: "I'm eating bread."},
+	}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("Unexpected pairs: %+v", pairs)
+	}
+}
+
+func TestPairReaderMalformedLine(t *testing.T) {
+	reader := NewPairReader(strings.NewReader("74172\tパンを食べる。\n"))
+
+	if _, err := reader.Next(); err == nil {
+		t.Errorf("Expected an error for a line without translation")
+	}
+}
+
+func TestIndexReader(t *testing.T) {
+	reader := NewIndexReader(strings.NewReader("74172\t1276\tパン~ を 食べる{食べた}\n"))
+
+	index, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Error reading index: %v", err)
+	}
+
+	expected := Index{
+		SentenceID: "74172",
+		MeaningID:  "1276",
+		Headwords: []Headword{
+			{Word: "パン", Checked: true},
+			{Word: "を"},
+			{Word: "食べる", Form: "食べた"},
+		},
+	}
+	if !reflect.DeepEqual(index, expected) {
+		t.Errorf("Unexpected index: %+v", index)
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestParseHeadword(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Headword
+	}{
+		{"は", Headword{Word: "は"}},
+		{"二十歳(はたち){20歳}", Headword{Word: "二十歳", Reading: "はたち", Form: "20歳"}},
+		{"になる[01]{になりました}", Headword{Word: "になる", Sense: 1, Form: "になりました"}},
+		{"彼(かれ)[01]{彼}~", Headword{Word: "彼", Reading: "かれ", Sense: 1, Form: "彼", Checked: true}},
+		{"其の{その}~", Headword{Word: "其の", Form: "その", Checked: true}},
+		{"壊れた(こわれた", Headword{Word: "壊れた", Reading: "こわれた"}},
+	}
+
+	for _, tc := range testCases {
+		if got := ParseHeadword(tc.input); got != tc.expected {
+			t.Errorf("ParseHeadword(%q) = %+v, want %+v", tc.input, got, tc.expected)
+		}
+	}
+}
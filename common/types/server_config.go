@@ -1,7 +1,91 @@
 package types
 
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/izquiratops/tango/common/logging"
+)
+
 type ServerConfig struct {
 	JmdictVersion  string
-	MongoURI       string
-	MongoRunsLocal bool
+	DataDir        string // Dictionary sources and every imported release
+	StorageBackend string // "mongo" or "bolt"
+	Listen         string // Address the client serves on
+	Mongo          MongoConfig
+	Search         SearchConfig
+	LogFormat      logging.Format
+	LogLevel       slog.Level
+	HTTP           HTTPConfig
+}
+
+// MongoConfig is either a full connection URI, or the parts to build one
+type MongoConfig struct {
+	URI      string // Used as is when set, the other fields are ignored
+	Host     string
+	Port     int
+	User     string
+	Password string
+}
+
+// ConnectionURI returns the URI given as is, or the one built from the other fields
+func (m MongoConfig) ConnectionURI() string {
+	if m.URI != "" {
+		return m.URI
+	}
+
+	uri := url.URL{Scheme: "mongodb", Host: net.JoinHostPort(m.Host, strconv.Itoa(m.Port))}
+	if m.User != "" && m.Password != "" {
+		uri.User = url.UserPassword(m.User, m.Password)
+	}
+
+	return uri.String()
+}
+
+// IsLocal tells if MongoDB runs on this machine
+func (m MongoConfig) IsLocal() bool {
+	host := m.Host
+	if m.URI != "" {
+		uri, err := url.Parse(m.URI)
+		if err != nil {
+			return false
+		}
+		host = uri.Hostname()
+	}
+
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+func (m MongoConfig) String() string {
+	return fmt.Sprintf("MongoConfig{%s}", RedactURI(m.ConnectionURI()))
+}
+
+// RedactURI hides the password of a URI, so it can be printed
+func RedactURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "(unparsable URI)"
+	}
+
+	return parsed.Redacted()
+}
+
+// SearchConfig sets the page size of searches and how they're cached
+type SearchConfig struct {
+	DefaultSize int           // Results per page when the request doesn't ask for a size
+	MaxSize     int           // Largest page size a request can ask for
+	CacheSize   int           // Searches kept in memory, the least recently used are dropped first
+	CacheTTL    time.Duration // Time a search result is served from memory
+}
+
+// HTTPConfig bounds how long the client spends on a connection, and on its way out
+type HTTPConfig struct {
+	ReadTimeout     time.Duration // Reading the whole request
+	WriteTimeout    time.Duration // From the end of the request headers to the end of the response
+	IdleTimeout     time.Duration // Keep-alive connections waiting for the next request
+	ShutdownTimeout time.Duration // In-flight requests draining after SIGINT or SIGTERM
 }
@@ -3,8 +3,8 @@ module github.com/izquiratops/tango/import
 go 1.23.0
 
 require (
+	github.com/blevesearch/bleve/v2 v2.4.4
 	github.com/izquiratops/tango/common v0.0.0
-	go.mongodb.org/mongo-driver v1.17.3
 )
 
 replace github.com/izquiratops/tango/common => ../common
@@ -12,7 +12,6 @@ replace github.com/izquiratops/tango/common => ../common
 require (
 	github.com/RoaringBitmap/roaring v1.9.3 // indirect
 	github.com/bits-and-blooms/bitset v1.12.0 // indirect
-	github.com/blevesearch/bleve/v2 v2.4.4 // indirect
 	github.com/blevesearch/bleve_index_api v1.1.12 // indirect
 	github.com/blevesearch/geo v0.1.20 // indirect
 	github.com/blevesearch/go-faiss v1.0.24 // indirect
@@ -42,8 +41,10 @@ require (
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
 	go.etcd.io/bbolt v1.3.7 // indirect
+	go.mongodb.org/mongo-driver v1.17.3 // indirect
 	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/jmdict"
+)
+
+// Aligner splits the reading of every form over its kanji. Forms found in the JmdictFurigana
+// data file use its alignment, the rest are aligned with the Kanjidic2 readings.
+type Aligner struct {
+	readings furigana.Readings
+	known    map[formKey][]furigana.Span
+}
+
+type formKey struct {
+	word    string
+	reading string
+}
+
+// furiganaEntry is a form of the JmdictFurigana data file (https://github.com/Doublevil/JmdictFurigana)
+type furiganaEntry struct {
+	Text     string `json:"text"`
+	Reading  string `json:"reading"`
+	Furigana []struct {
+		Ruby string `json:"ruby"`
+		Rt   string `json:"rt"`
+	} `json:"furigana"`
+}
+
+// LoadAligner reads the kanji readings and the JmdictFurigana data file, both are optional.
+// Without them forms are still aligned around their kana, but runs of kanji can't be split.
+func LoadAligner(kanjiPath string, furiganaPath string) (*Aligner, error) {
+	aligner := &Aligner{}
+
+	if kanjiPath != "" {
+		readings, err := loadKanjiReadings(kanjiPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading kanji readings: %v", err)
+		}
+		aligner.readings = readings
+	}
+
+	if furiganaPath != "" {
+		known, err := loadFuriganaFile(furiganaPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading furigana file: %v", err)
+		}
+		aligner.known = known
+	}
+
+	fmt.Printf("Loaded readings of %d kanji and %d aligned forms for furigana\n", len(aligner.readings), len(aligner.known))
+	return aligner, nil
+}
+
+// Align stores the reading of every kanji in the form, forms that can't be aligned keep
+// their whole reading. A nil Aligner leaves the forms untouched.
+func (a *Aligner) Align(form *database.Furigana) {
+	if a == nil || form.Reading == "" {
+		return
+	}
+
+	segments, ok := a.known[formKey{form.Word, form.Reading}]
+	if !ok {
+		segments = furigana.Align(form.Word, form.Reading, a.readings)
+	}
+
+	// A single span is what the whole reading already says
+	if len(segments) > 1 {
+		form.Segments = segments
+	}
+}
+
+// AlignForms aligns the main form and the other forms of a word or name
+func (a *Aligner) AlignForms(main *database.Furigana, others []database.Furigana) {
+	a.Align(main)
+	for i := range others {
+		a.Align(&others[i])
+	}
+}
+
+func loadKanjiReadings(path string) (furigana.Readings, error) {
+	source, err := openSource(path)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	decoder, err := jmdict.NewKanjidic2Decoder(source)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	readings := furigana.Readings{}
+	for {
+		character, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JSON: %v", err)
+		}
+
+		addKanjiReadings(readings, &character)
+	}
+
+	return readings, nil
+}
+
+func addKanjiReadings(readings furigana.Readings, c *jmdict.Kanjidic2Character) {
+	literal := []rune(c.Literal)
+	if len(literal) != 1 || c.ReadingMeaning == nil {
+		return
+	}
+
+	for _, group := range c.ReadingMeaning.Groups {
+		for _, reading := range group.Readings {
+			if reading.Type == jmdict.JapaneseOn || reading.Type == jmdict.JapaneseKun {
+				readings.Add(literal[0], reading.Value)
+			}
+		}
+	}
+
+	for _, reading := range c.ReadingMeaning.Nanori {
+		readings.Add(literal[0], reading)
+	}
+}
+
+// loadFuriganaFile streams the JmdictFurigana array, it's published with a byte order mark
+func loadFuriganaFile(path string) (map[formKey][]furigana.Span, error) {
+	source, err := openSource(path)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	reader := bufio.NewReader(source)
+	if bom, _, err := reader.ReadRune(); err == nil && bom != '\uFEFF' {
+		reader.UnreadRune()
+	}
+
+	decoder := json.NewDecoder(reader)
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	known := make(map[formKey][]furigana.Span)
+	for decoder.More() {
+		var entry furiganaEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("error decoding JSON: %v", err)
+		}
+
+		var spans []furigana.Span
+		for _, part := range entry.Furigana {
+			spans = append(spans, furigana.Span{Text: part.Ruby, Reading: part.Rt})
+		}
+		known[formKey{entry.Text, entry.Reading}] = spans
+	}
+
+	return known, nil
+}
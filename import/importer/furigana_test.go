@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/furigana"
+	"github.com/izquiratops/tango/common/jmdict"
+)
+
+func TestAddKanjiReadings(t *testing.T) {
+	readings := furigana.Readings{}
+	addKanjiReadings(readings, &jmdict.Kanjidic2Character{
+		Literal: "取",
+		ReadingMeaning: &jmdict.Kanjidic2ReadingMeaning{
+			Groups: []jmdict.Kanjidic2ReadingMeaningGroup{{
+				Readings: []jmdict.Kanjidic2Reading{
+					{Type: jmdict.Pinyin, Value: "qu3"},
+					{Type: jmdict.JapaneseOn, Value: "シュ"},
+					{Type: jmdict.JapaneseKun, Value: "と.る"},
+				},
+			}},
+			Nanori: []string{"とり"},
+		},
+	})
+
+	expected := []string{"しゅ", "と", "とる", "とり"}
+	if got := readings['取']; !reflect.DeepEqual(got, expected) {
+		t.Errorf("readings = %v, want %v", got, expected)
+	}
+}
+
+func TestAlignerAlign(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "JmdictFurigana.json")
+
+	// The data file starts with a byte order mark
+	data := "\uFEFF" + `[{"text":"今日","reading":"きょう","furigana":[{"ruby":"今日","rt":"きょう"}]},` +
+		`{"text":"大人","reading":"おとな","furigana":[{"ruby":"大人","rt":"おとな"}]},` +
+		`{"text":"取り扱い","reading":"とりあつかい","furigana":[{"ruby":"取","rt":"と"},{"ruby":"り"},{"ruby":"扱","rt":"あつか"},{"ruby":"い"}]}]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	aligner, err := LoadAligner("", path)
+	if err != nil {
+		t.Fatalf("LoadAligner() error = %v", err)
+	}
+
+	main := database.Furigana{Word: "取り扱い", Reading: "とりあつかい"}
+	others := []database.Furigana{
+		{Word: "今日", Reading: "きょう"},
+		{Word: "食べる", Reading: "たべる"},
+		{Word: "パン"},
+	}
+	aligner.AlignForms(&main, others)
+
+	expected := []furigana.Span{{Text: "取", Reading: "と"}, {Text: "り"}, {Text: "扱", Reading: "あつか"}, {Text: "い"}}
+	if !reflect.DeepEqual(main.Segments, expected) {
+		t.Errorf("main segments = %v, want %v", main.Segments, expected)
+	}
+
+	// Whole word readings don't need segments
+	if others[0].Segments != nil {
+		t.Errorf("今日 segments = %v, want nil", others[0].Segments)
+	}
+
+	// Forms missing from the file are aligned around their kana
+	expected = []furigana.Span{{Text: "食", Reading: "た"}, {Text: "べる"}}
+	if !reflect.DeepEqual(others[1].Segments, expected) {
+		t.Errorf("食べる segments = %v, want %v", others[1].Segments, expected)
+	}
+
+	if others[2].Segments != nil {
+		t.Errorf("パン segments = %v, want nil", others[2].Segments)
+	}
+}
@@ -0,0 +1,196 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+)
+
+const (
+	numWorkers = 3
+	batchSize  = 1000
+	sampleSize = 100 // Words kept aside to validate the import
+)
+
+type ImportStats struct {
+	DictDate       string
+	Words          int
+	Kanji          int
+	Radicals       int
+	Names          int
+	Sentences      int
+	Tags           int
+	SampleIDs      []string // Random sample of imported word IDs
+	KanjiSample    []string // Random sample of imported kanji
+	NameSample     []string // Random sample of imported name IDs
+	SentenceSample []string // Random sample of imported sentence IDs
+	Duration       time.Duration
+}
+
+// Import streams the words of a jmdict-simplified release into the database, aligning the furigana of their forms
+func Import(db *database.Database, sourcePath string, aligner *Aligner) (*ImportStats, error) {
+	source, err := openSource(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	decoder, err := jmdict.NewJMdictDecoder(source)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	metadata := decoder.Metadata()
+	fmt.Printf("Importing JMdict %v (%v)\n", metadata.Version, metadata.DictDate)
+
+	if err := importTags(db, metadata.Tags); err != nil {
+		return nil, err
+	}
+
+	stats := &ImportStats{
+		DictDate: metadata.DictDate,
+		Tags:     len(metadata.Tags),
+	}
+
+	entriesChan := make(chan jmdict.JMdictWord, batchSize)
+	errorsChan := make(chan error, numWorkers)
+	var wg sync.WaitGroup
+
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go bulkImportJmdictEntries(entriesChan, errorsChan, &wg, db, aligner)
+	}
+
+	startTime := time.Now()
+	for {
+		entry, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			close(entriesChan)
+			wg.Wait()
+			return nil, fmt.Errorf("error decoding JSON: %v", err)
+		}
+
+		select {
+		case err := <-errorsChan:
+			close(entriesChan)
+			wg.Wait()
+			return nil, fmt.Errorf("worker error: %v", err)
+		case entriesChan <- entry:
+			stats.Words++
+			stats.SampleIDs = addSample(stats.SampleIDs, stats.Words, entry.ID)
+		}
+	}
+
+	close(entriesChan)
+	wg.Wait()
+
+	// Workers may have failed on the very last batches
+	select {
+	case err := <-errorsChan:
+		return nil, fmt.Errorf("worker error: %v", err)
+	default:
+	}
+
+	stats.Duration = time.Since(startTime)
+
+	fmt.Printf("Dictionary import completed. Processed %d entries in %v\n", stats.Words, stats.Duration)
+	return stats, nil
+}
+
+// addSample keeps a uniform random sample of the IDs seen so far (reservoir sampling)
+func addSample(sample []string, seen int, id string) []string {
+	if len(sample) < sampleSize {
+		return append(sample, id)
+	}
+
+	if i := rand.Intn(seen); i < sampleSize {
+		sample[i] = id
+	}
+
+	return sample
+}
+
+func importTags(db *database.Database, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	dbTags := make([]database.Tag, 0, len(tags))
+	for name, description := range tags {
+		dbTags = append(dbTags, database.Tag{
+			Name:        name,
+			Description: description,
+		})
+	}
+
+	if err := db.Store.PutTags(context.Background(), dbTags); err != nil {
+		return fmt.Errorf("error writing tags: %v", err)
+	}
+
+	fmt.Printf("Imported %d tags\n", len(tags))
+	return nil
+}
+
+func bulkImportJmdictEntries(jsonEntries <-chan jmdict.JMdictWord, errors chan<- error, wg *sync.WaitGroup, di *database.Database, aligner *Aligner) {
+	defer wg.Done()
+
+	ctx := context.Background()
+	storeBatch := make([]database.Word, 0, batchSize)
+	bleveBatch := di.BleveIndex.NewBatch()
+
+	for jsonEntry := range jsonEntries {
+		// Save it as DatabaseEntry
+		word := ToWord(&jsonEntry)
+		aligner.AlignForms(&word.MainWord, word.OtherForms)
+		storeBatch = append(storeBatch, word)
+
+		// Prepare Bleve
+		bleveEntry, err := ToWordSearchable(&jsonEntry)
+		if err != nil {
+			errors <- err
+			return
+		}
+
+		if err := bleveBatch.Index(jsonEntry.ID, bleveEntry); err != nil {
+			errors <- fmt.Errorf("error indexing in Bleve: %v", err)
+			return
+		}
+
+		if len(storeBatch) >= batchSize {
+			if err := di.Store.PutWords(ctx, storeBatch); err != nil {
+				errors <- fmt.Errorf("error writing to store: %v", err)
+				return
+			}
+
+			if err := di.BleveIndex.Batch(bleveBatch); err != nil {
+				errors <- fmt.Errorf("error writing to Bleve: %v", err)
+				return
+			}
+
+			storeBatch = storeBatch[:0]
+			bleveBatch = di.BleveIndex.NewBatch()
+		}
+	}
+
+	// Process last batch. This runs the batch if storeBatch never reached the batchSize threshold
+	if len(storeBatch) > 0 {
+		if err := di.Store.PutWords(ctx, storeBatch); err != nil {
+			errors <- fmt.Errorf("error writing final batch to store: %v", err)
+			return
+		}
+
+		if err := di.BleveIndex.Batch(bleveBatch); err != nil {
+			errors <- fmt.Errorf("error writing final batch to Bleve: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+	"github.com/izquiratops/tango/common/kana"
+	"github.com/izquiratops/tango/common/utils"
+)
+
+// ToKanji converts a Kanjidic2 character, components are the radicals it's made of (can be nil)
+func ToKanji(c *jmdict.Kanjidic2Character, components []string) database.Kanji {
+	entry := database.Kanji{
+		Literal:      c.Literal,
+		OnReadings:   make([]string, 0),
+		KunReadings:  make([]string, 0),
+		Nanori:       make([]string, 0),
+		Meanings:     make([]string, 0),
+		RadicalNames: c.Misc.RadicalNames,
+		Components:   components,
+	}
+
+	if len(c.Misc.StrokeCounts) > 0 {
+		// The first count is the accepted one, the rest are common miscounts
+		entry.StrokeCount = c.Misc.StrokeCounts[0]
+	}
+	if c.Misc.Grade != nil {
+		entry.Grade = *c.Misc.Grade
+	}
+	if c.Misc.JLPTLevel != nil {
+		entry.JLPT = *c.Misc.JLPTLevel
+	}
+	if c.Misc.Frequency != nil {
+		entry.Frequency = *c.Misc.Frequency
+	}
+
+	for _, radical := range c.Radicals {
+		if radical.Type == jmdict.Classical {
+			entry.Radical = radical.Value
+		}
+	}
+
+	if c.ReadingMeaning == nil {
+		return entry
+	}
+
+	entry.Nanori = append(entry.Nanori, c.ReadingMeaning.Nanori...)
+
+	for _, group := range c.ReadingMeaning.Groups {
+		for _, reading := range group.Readings {
+			switch reading.Type {
+			case jmdict.JapaneseOn:
+				entry.OnReadings = append(entry.OnReadings, reading.Value)
+			case jmdict.JapaneseKun:
+				entry.KunReadings = append(entry.KunReadings, reading.Value)
+			}
+		}
+
+		for _, meaning := range group.Meanings {
+			if meaning.Lang == "en" {
+				entry.Meanings = append(entry.Meanings, meaning.Value)
+			}
+		}
+	}
+
+	return entry
+}
+
+func ToKanjiSearchable(k *database.Kanji) database.KanjiSearchable {
+	entry := database.KanjiSearchable{
+		Literal:     k.Literal,
+		Meanings:    k.Meanings,
+		Readings:    make([]string, 0),
+		Components:  k.Components,
+		StrokeCount: k.StrokeCount,
+	}
+
+	for _, reading := range k.OnReadings {
+		entry.Readings = appendReading(entry.Readings, kana.ToHiraganaFromKatakana(reading))
+	}
+
+	for _, reading := range k.KunReadings {
+		// Kun readings look like 'た.べる' or '-ぶ', the markers are only for display
+		reading = strings.NewReplacer(".", "", "-", "").Replace(reading)
+		entry.Readings = appendReading(entry.Readings, reading)
+	}
+
+	return entry
+}
+
+func appendReading(readings []string, reading string) []string {
+	if reading == "" || utils.ContainsString(readings, reading) {
+		return readings
+	}
+
+	return append(readings, reading)
+}
+
+// ImportKanji streams the characters of a jmdict-simplified kanjidic2 release into the database.
+// When radicals are given, every kanji is stored with its components and the radicals are imported too.
+func ImportKanji(db *database.Database, sourcePath string, radicals *Radicals, stats *ImportStats) error {
+	source, err := openSource(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	decoder, err := jmdict.NewKanjidic2Decoder(source)
+	if err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	metadata := decoder.Metadata()
+	fmt.Printf("Importing Kanjidic2 %v (%v)\n", metadata.Version, metadata.DictDate)
+
+	var components map[string][]string
+	if radicals != nil {
+		components = radicals.Components
+
+		if err := importRadicals(db, radicals.Radicals, stats); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	storeBatch := make([]database.Kanji, 0, batchSize)
+	bleveBatch := db.KanjiIndex.NewBatch()
+
+	flush := func() error {
+		if err := db.Store.PutKanji(ctx, storeBatch); err != nil {
+			return fmt.Errorf("error writing kanji to store: %v", err)
+		}
+		if err := db.KanjiIndex.Batch(bleveBatch); err != nil {
+			return fmt.Errorf("error writing kanji to Bleve: %v", err)
+		}
+
+		storeBatch = storeBatch[:0]
+		bleveBatch = db.KanjiIndex.NewBatch()
+		return nil
+	}
+
+	for {
+		character, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+
+		kanji := ToKanji(&character, components[character.Literal])
+		storeBatch = append(storeBatch, kanji)
+
+		if err := bleveBatch.Index(kanji.Literal, ToKanjiSearchable(&kanji)); err != nil {
+			return fmt.Errorf("error indexing kanji in Bleve: %v", err)
+		}
+
+		stats.Kanji++
+		stats.KanjiSample = addSample(stats.KanjiSample, stats.Kanji, kanji.Literal)
+
+		if len(storeBatch) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(storeBatch) > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Kanji import completed. Processed %d characters\n", stats.Kanji)
+	return nil
+}
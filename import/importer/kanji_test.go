@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+)
+
+func TestToKanji(t *testing.T) {
+	grade, frequency, jlpt := 2, 301, 4
+	input := jmdict.Kanjidic2Character{
+		Literal: "語",
+		Radicals: []jmdict.Kanjidic2Radical{
+			{Type: jmdict.Classical, Value: 149},
+			{Type: jmdict.NelsonC, Value: 149},
+		},
+		Misc: jmdict.Kanjidic2Misc{
+			Grade:        &grade,
+			StrokeCounts: []int{14},
+			Frequency:    &frequency,
+			JLPTLevel:    &jlpt,
+		},
+		ReadingMeaning: &jmdict.Kanjidic2ReadingMeaning{
+			Groups: []jmdict.Kanjidic2ReadingMeaningGroup{
+				{
+					Readings: []jmdict.Kanjidic2Reading{
+						{Type: jmdict.Pinyin, Value: "yu3"},
+						{Type: jmdict.JapaneseOn, Value: "ゴ"},
+						{Type: jmdict.JapaneseKun, Value: "かた.る"},
+						{Type: jmdict.JapaneseKun, Value: "かた.らう"},
+					},
+					Meanings: []jmdict.Kanjidic2Meaning{
+						{Lang: "en", Value: "word"},
+						{Lang: "fr", Value: "mot"},
+						{Lang: "en", Value: "speech"},
+					},
+				},
+			},
+			Nanori: []string{"ご"},
+		},
+	}
+
+	expected := database.Kanji{
+		Literal:     "語",
+		OnReadings:  []string{"ゴ"},
+		KunReadings: []string{"かた.る", "かた.らう"},
+		Nanori:      []string{"ご"},
+		Meanings:    []string{"word", "speech"},
+		StrokeCount: 14,
+		Grade:       2,
+		JLPT:        4,
+		Frequency:   301,
+		Radical:     149,
+		Components:  []string{"言", "五", "口"},
+	}
+
+	kanji := ToKanji(&input, []string{"言", "五", "口"})
+	if !reflect.DeepEqual(kanji, expected) {
+		t.Errorf("ToKanji() = %+v, want %+v", kanji, expected)
+	}
+
+	searchable := ToKanjiSearchable(&kanji)
+	if expectedReadings := []string{"ご", "かたる", "かたらう"}; !reflect.DeepEqual(searchable.Readings, expectedReadings) {
+		t.Errorf("ToKanjiSearchable() readings = %v, want %v", searchable.Readings, expectedReadings)
+	}
+	if searchable.StrokeCount != 14 || len(searchable.Components) != 3 {
+		t.Errorf("ToKanjiSearchable() = %+v, want 14 strokes and 3 components", searchable)
+	}
+}
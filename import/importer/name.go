@@ -0,0 +1,213 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+	"github.com/izquiratops/tango/common/kana"
+	"github.com/izquiratops/tango/common/utils"
+)
+
+func ToName(word *jmdict.JMnedictWord) database.Name {
+	entry := database.Name{
+		ID:           word.ID,
+		Types:        make([]string, 0),
+		Translations: make([]string, 0),
+	}
+
+	for _, form := range nameForms(word) {
+		if entry.MainWord.Word == "" {
+			entry.MainWord = form
+		} else {
+			entry.OtherForms = append(entry.OtherForms, form)
+		}
+	}
+
+	for _, translation := range word.Translation {
+		for _, nameType := range translation.Type {
+			if !utils.ContainsString(entry.Types, nameType) {
+				entry.Types = append(entry.Types, nameType)
+			}
+		}
+
+		for _, t := range translation.Translation {
+			entry.Translations = append(entry.Translations, t.Text)
+		}
+	}
+
+	return entry
+}
+
+// nameForms pairs every kanji with the readings that apply to it, like processKanjiWord does for words
+func nameForms(word *jmdict.JMnedictWord) []database.Furigana {
+	var forms []database.Furigana
+
+	if len(word.Kanji) == 0 {
+		for _, k := range word.Kana {
+			forms = append(forms, database.Furigana{Word: k.Text})
+		}
+		return forms
+	}
+
+	for _, k := range word.Kana {
+		for _, kanji := range word.Kanji {
+			for _, kanjiApplied := range k.AppliesToKanji {
+				if kanjiApplied == kanji.Text || kanjiApplied == "*" {
+					forms = append(forms, database.Furigana{
+						Word:    kanji.Text,
+						Reading: k.Text,
+					})
+				}
+			}
+		}
+	}
+
+	return forms
+}
+
+func ToNameSearchable(word *jmdict.JMnedictWord) (database.NameSearchable, error) {
+	entry := database.NameSearchable{
+		ID:         word.ID,
+		KanjiExact: make([]string, 0),
+		KanjiChar:  make([]string, 0),
+		KanaExact:  make([]string, 0),
+		KanaChar:   make([]string, 0),
+		Meanings:   make([]string, 0),
+		Romaji:     make([]string, 0),
+	}
+
+	for _, k := range word.Kanji {
+		if k.Text == "" {
+			return entry, fmt.Errorf("emtpy field at %v", word.ID)
+		}
+
+		entry.KanjiExact = append(entry.KanjiExact, k.Text)
+		entry.KanjiChar = append(entry.KanjiChar, k.Text)
+	}
+
+	for _, k := range word.Kana {
+		if k.Text == "" {
+			return entry, fmt.Errorf("emtpy field at %v", word.ID)
+		}
+
+		entry.KanaExact = append(entry.KanaExact, k.Text)
+		entry.KanaChar = append(entry.KanaChar, k.Text)
+
+		if romaji := kana.ToRomaji(k.Text); !utils.ContainsString(entry.Romaji, romaji) {
+			entry.Romaji = append(entry.Romaji, romaji)
+		}
+	}
+
+	for _, translation := range word.Translation {
+		for _, t := range translation.Translation {
+			entry.Meanings = append(entry.Meanings, t.Text)
+		}
+	}
+
+	return entry, nil
+}
+
+// ImportNames streams the names of a jmdict-simplified JMnedict release into the database, aligning their furigana like words
+func ImportNames(db *database.Database, sourcePath string, aligner *Aligner, stats *ImportStats) error {
+	source, err := openSource(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	decoder, err := jmdict.NewJMnedictDecoder(source)
+	if err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	metadata := decoder.Metadata()
+	fmt.Printf("Importing JMnedict %v (%v)\n", metadata.Version, metadata.DictDate)
+
+	added, err := importMissingTags(db, metadata.Tags)
+	if err != nil {
+		return err
+	}
+	stats.Tags += added
+
+	ctx := context.Background()
+	storeBatch := make([]database.Name, 0, batchSize)
+	bleveBatch := db.NamesIndex.NewBatch()
+
+	flush := func() error {
+		if err := db.Store.PutNames(ctx, storeBatch); err != nil {
+			return fmt.Errorf("error writing names to store: %v", err)
+		}
+		if err := db.NamesIndex.Batch(bleveBatch); err != nil {
+			return fmt.Errorf("error writing names to Bleve: %v", err)
+		}
+
+		storeBatch = storeBatch[:0]
+		bleveBatch = db.NamesIndex.NewBatch()
+		return nil
+	}
+
+	for {
+		word, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+
+		bleveEntry, err := ToNameSearchable(&word)
+		if err != nil {
+			return err
+		}
+
+		if err := bleveBatch.Index(word.ID, bleveEntry); err != nil {
+			return fmt.Errorf("error indexing name in Bleve: %v", err)
+		}
+		name := ToName(&word)
+		aligner.AlignForms(&name.MainWord, name.OtherForms)
+		storeBatch = append(storeBatch, name)
+
+		stats.Names++
+		stats.NameSample = addSample(stats.NameSample, stats.Names, word.ID)
+
+		if len(storeBatch) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(storeBatch) > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Names import completed. Processed %d entries\n", stats.Names)
+	return nil
+}
+
+// importMissingTags adds the JMnedict tags that JMdict doesn't already have, returning how many were added
+func importMissingTags(db *database.Database, tags map[string]string) (int, error) {
+	existing, err := db.Store.GetTags(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("error reading tags: %v", err)
+	}
+
+	missing := make(map[string]string, len(tags))
+	for name, description := range tags {
+		missing[name] = description
+	}
+	for _, tag := range existing {
+		delete(missing, tag.Name)
+	}
+
+	if err := importTags(db, missing); err != nil {
+		return 0, err
+	}
+
+	return len(missing), nil
+}
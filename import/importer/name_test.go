@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+)
+
+func TestToName(t *testing.T) {
+	input := jmdict.JMnedictWord{
+		ID: "5000000",
+		Kanji: []jmdict.JMnedictKanji{
+			{Text: "田中"},
+			{Text: "田仲"},
+		},
+		Kana: []jmdict.JMnedictKana{
+			{Text: "たなか", AppliesToKanji: []string{"*"}},
+			{Text: "でんちゅう", AppliesToKanji: []string{"田中"}},
+		},
+		Translation: []jmdict.JMnedictTranslation{
+			{Type: []string{"surname"}, Translation: []jmdict.JMnedictTranslationTranslation{{Lang: "eng", Text: "Tanaka"}}},
+			{Type: []string{"surname", "place"}, Translation: []jmdict.JMnedictTranslationTranslation{{Lang: "eng", Text: "Denchuu"}}},
+		},
+	}
+
+	expected := database.Name{
+		ID:       "5000000",
+		MainWord: database.Furigana{Word: "田中", Reading: "たなか"},
+		OtherForms: []database.Furigana{
+			{Word: "田仲", Reading: "たなか"},
+			{Word: "田中", Reading: "でんちゅう"},
+		},
+		Types:        []string{"surname", "place"},
+		Translations: []string{"Tanaka", "Denchuu"},
+	}
+
+	if name := ToName(&input); !reflect.DeepEqual(name, expected) {
+		t.Errorf("ToName() = %+v, want %+v", name, expected)
+	}
+
+	searchable, err := ToNameSearchable(&input)
+	if err != nil {
+		t.Fatalf("ToNameSearchable() error = %v", err)
+	}
+	if expectedRomaji := []string{"tanaka", "denchuu"}; !reflect.DeepEqual(searchable.Romaji, expectedRomaji) {
+		t.Errorf("ToNameSearchable() romaji = %v, want %v", searchable.Romaji, expectedRomaji)
+	}
+}
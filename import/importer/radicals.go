@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+)
+
+// Radicals is what's read from the jmdict-simplified kradfile and radkfile releases
+type Radicals struct {
+	Components map[string][]string // Kanji to the radicals it's made of
+	Radicals   []database.Radical
+}
+
+// LoadRadicals reads both files at once, they're small enough to fit in memory
+func LoadRadicals(kradfilePath string, radkfilePath string) (*Radicals, error) {
+	var kradfile jmdict.Kradfile
+	if err := decodeSource(kradfilePath, &kradfile); err != nil {
+		return nil, fmt.Errorf("error reading kradfile: %v", err)
+	}
+
+	var radkfile jmdict.Radkfile
+	if err := decodeSource(radkfilePath, &radkfile); err != nil {
+		return nil, fmt.Errorf("error reading radkfile: %v", err)
+	}
+
+	fmt.Printf("Loaded %d kanji components and %d radicals (%v)\n", len(kradfile.Kanji), len(radkfile.Radicals), radkfile.Version)
+
+	radicals := make([]database.Radical, 0, len(radkfile.Radicals))
+	for literal, info := range radkfile.Radicals {
+		radicals = append(radicals, database.Radical{
+			Literal:     literal,
+			StrokeCount: info.StrokeCount,
+		})
+	}
+
+	return &Radicals{
+		Components: kradfile.Kanji,
+		Radicals:   radicals,
+	}, nil
+}
+
+func decodeSource(path string, v any) error {
+	source, err := openSource(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	if err := json.NewDecoder(source).Decode(v); err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	return nil
+}
+
+func importRadicals(db *database.Database, radicals []database.Radical, stats *ImportStats) error {
+	if len(radicals) == 0 {
+		return nil
+	}
+
+	if err := db.Store.PutRadicals(context.Background(), radicals); err != nil {
+		return fmt.Errorf("error writing radicals: %v", err)
+	}
+
+	stats.Radicals = len(radicals)
+	fmt.Printf("Imported %d radicals\n", len(radicals))
+	return nil
+}
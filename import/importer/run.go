@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// Options are the sources given on the command line, the missing ones are looked up in the data folder
+type Options struct {
+	Source          string
+	KanjiSource     string
+	Kradfile        string
+	Radkfile        string
+	NamesSource     string
+	Sentences       string
+	SentenceIndices string
+	Furigana        string
+	Rollback        bool // Serve the previous release again instead of importing
+}
+
+// RegisterFlags adds the flags of the importer to the flag set
+func RegisterFlags(flags *flag.FlagSet) *Options {
+	options := &Options{}
+	flags.StringVar(&options.Source, "source", "", "JMdict release to import (.json, .zip or .tgz). Defaults to the newest jmdict-eng-$TANGO_VERSION file in the data folder")
+	flags.StringVar(&options.KanjiSource, "kanji-source", "", "Kanjidic2 release to import (.json, .zip or .tgz). Defaults to the newest kanjidic2-en-$TANGO_VERSION file in the data folder")
+	flags.StringVar(&options.Kradfile, "kradfile", "", "Kradfile release to import (.json, .zip or .tgz). Defaults to the newest kradfile-$TANGO_VERSION file in the data folder")
+	flags.StringVar(&options.Radkfile, "radkfile", "", "Radkfile release to import (.json, .zip or .tgz). Defaults to the newest radkfile-$TANGO_VERSION file in the data folder")
+	flags.StringVar(&options.NamesSource, "names-source", "", "JMnedict release to import (.json, .zip or .tgz). Defaults to the newest jmnedict-all-$TANGO_VERSION file in the data folder")
+	flags.StringVar(&options.Sentences, "sentences", "", "Tatoeba Japanese-English sentence pairs (.tsv). Defaults to the newest jpn-eng file in the data folder")
+	flags.StringVar(&options.SentenceIndices, "sentence-indices", "", "Tatoeba Japanese indices linking sentences to words (.csv). Defaults to the newest jpn_indices file in the data folder")
+	flags.StringVar(&options.Furigana, "furigana", "", "JmdictFurigana data file with the reading of every kanji (.json or .zip). Defaults to the newest JmdictFurigana file in the data folder")
+	flags.BoolVar(&options.Rollback, "rollback", false, "Serve the previous import again instead of importing")
+
+	return options
+}
+
+// Run imports the sources into a new release and promotes it once it's validated, or rolls
+// back to the previous release
+func Run(config *types.ServerConfig, options Options) error {
+	if options.Rollback {
+		release, err := database.RollbackRelease(config.DataDir, config.JmdictVersion)
+		if err != nil {
+			return fmt.Errorf("error rolling back: %w", err)
+		}
+
+		fmt.Printf("Rolled back, now serving release %v\n", release.Generation)
+		return nil
+	}
+
+	sources, err := findSources(config, options)
+	if err != nil {
+		return err
+	}
+
+	// Everything is written into a new release, the one being served isn't touched until it's promoted
+	release := database.NewRelease(config.DataDir, config.JmdictVersion)
+	if err := stageRelease(config, release, sources); err != nil {
+		if deleteErr := database.DeleteRelease(config, release); deleteErr != nil {
+			fmt.Fprintf(os.Stderr, "Error removing staging release %v: %v\n", release.Generation, deleteErr)
+		}
+		return err
+	}
+
+	if err := database.PromoteRelease(release); err != nil {
+		return fmt.Errorf("error promoting release: %w", err)
+	}
+
+	// Only the current and previous releases are kept
+	if err := pruneReleases(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error removing old releases: %v\n", err)
+	}
+
+	fmt.Printf("\n==============================================\n")
+	fmt.Printf("✅ IMPORT COMPLETED SUCCESSFULLY!\n")
+	fmt.Printf("==============================================\n\n")
+	fmt.Printf("Imported from: %s\n", sources.Words)
+	if sources.Kanji != "" {
+		fmt.Printf("Kanji imported from: %s\n", sources.Kanji)
+	}
+	if sources.hasRadicals() {
+		fmt.Printf("Radicals imported from: %s and %s\n", sources.Kradfile, sources.Radkfile)
+	}
+	if sources.Names != "" {
+		fmt.Printf("Names imported from: %s\n", sources.Names)
+	}
+	if sources.Sentences != "" {
+		fmt.Printf("Sentences imported from: %s\n", sources.Sentences)
+	}
+	if sources.SentenceIndices != "" {
+		fmt.Printf("Sentences linked to words with: %s\n", sources.SentenceIndices)
+	}
+	if sources.Furigana != "" {
+		fmt.Printf("Furigana aligned with: %s\n", sources.Furigana)
+	}
+	fmt.Printf("Release %v was promoted, it's stored in: %s\n", release.Generation, release.Path())
+
+	if !config.Mongo.IsLocal() {
+		fmt.Printf("\nNext step: Upload the release to your server using SCP:\n")
+		fmt.Printf("scp -r %s user@example.com:jmdict_source\n\n", database.ReleasesDir(config.DataDir, config.JmdictVersion))
+	}
+
+	return nil
+}
+
+// Sources are the files imported into a release, empty paths are skipped
+type Sources struct {
+	Words           string
+	Kanji           string
+	Kradfile        string
+	Radkfile        string
+	Names           string
+	Sentences       string // Tatoeba sentence pairs
+	SentenceIndices string // Tatoeba Japanese indices, without them sentences aren't linked to words
+	Furigana        string // JmdictFurigana data file, without it furigana is aligned with the kanji readings alone
+}
+
+// hasRadicals tells if radicals can be imported, they need both files and the kanji they refer to
+func (s Sources) hasRadicals() bool {
+	return s.Kanji != "" && s.Kradfile != "" && s.Radkfile != ""
+}
+
+// findSources resolves the files to import. Kanji, radicals, names and sentences are optional,
+// without them the release only has words.
+func findSources(config *types.ServerConfig, options Options) (Sources, error) {
+	sourcePath := options.Source
+	if sourcePath == "" {
+		var err error
+		sourcePath, err = findSource(config.DataDir, fmt.Sprintf("jmdict-eng-%v", config.JmdictVersion))
+		if err != nil {
+			return Sources{}, fmt.Errorf("error finding JMdict source: %w", err)
+		}
+	}
+
+	sources := Sources{
+		Words:     sourcePath,
+		Kanji:     optionalSource(config.DataDir, options.KanjiSource, "kanjidic2-en", config.JmdictVersion),
+		Names:     optionalSource(config.DataDir, options.NamesSource, "jmnedict-all", config.JmdictVersion),
+		Sentences: optionalFile(config.DataDir, options.Sentences, "jpn-eng", tatoebaExtensions),
+		Furigana:  optionalFile(config.DataDir, options.Furigana, "JmdictFurigana", sourceExtensions),
+	}
+	if sources.Kanji != "" {
+		sources.Kradfile = optionalSource(config.DataDir, options.Kradfile, "kradfile", config.JmdictVersion)
+		sources.Radkfile = optionalSource(config.DataDir, options.Radkfile, "radkfile", config.JmdictVersion)
+	}
+	if sources.Sentences != "" {
+		sources.SentenceIndices = optionalFile(config.DataDir, options.SentenceIndices, "jpn_indices", tatoebaExtensions)
+	}
+
+	return sources, nil
+}
+
+// optionalSource returns the path given by flag, or the newest release of the dictionary in the data folder
+func optionalSource(dataDir string, flagPath string, dictionary string, version string) string {
+	return optionalFile(dataDir, flagPath, fmt.Sprintf("%v-%v", dictionary, version), sourceExtensions)
+}
+
+// optionalFile returns the path given by flag, or the newest file in the data folder starting with prefix.
+// Tatoeba exports aren't versioned along JMdict, so they're only looked up by name.
+func optionalFile(dataDir string, flagPath string, prefix string, extensions []string) string {
+	if flagPath != "" {
+		return flagPath
+	}
+
+	path, err := findFile(dataDir, prefix, extensions)
+	if err != nil {
+		fmt.Printf("Skipping %v: %v\n", prefix, err)
+		return ""
+	}
+
+	return path
+}
+
+// stageRelease imports the sources into the release and validates it
+func stageRelease(config *types.ServerConfig, release database.Release, sources Sources) error {
+	startTime := time.Now()
+
+	var radicals *Radicals
+	if sources.hasRadicals() {
+		var err error
+		if radicals, err = LoadRadicals(sources.Kradfile, sources.Radkfile); err != nil {
+			return err
+		}
+	}
+
+	var forms WordForms
+	if sources.SentenceIndices != "" {
+		var err error
+		if forms, err = LoadWordForms(sources.Words); err != nil {
+			return err
+		}
+	}
+
+	aligner, err := LoadAligner(sources.Kanji, sources.Furigana)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.NewDatabase(context.Background(), config, release)
+	if err != nil {
+		return err
+	}
+
+	stats, err := Import(db, sources.Words, aligner)
+	if err == nil && sources.Kanji != "" {
+		err = ImportKanji(db, sources.Kanji, radicals, stats)
+	}
+	if err == nil && sources.Names != "" {
+		err = ImportNames(db, sources.Names, aligner, stats)
+	}
+	if err == nil && sources.Sentences != "" {
+		err = ImportSentences(db, sources.Sentences, sources.SentenceIndices, forms, stats)
+	}
+	if err == nil {
+		err = validateImport(db, stats)
+	}
+
+	// Closing flushes both the Bleve index and the store to disk
+	if closeErr := db.Close(context.Background()); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return release.WriteManifest(database.Manifest{
+		JmdictVersion: config.JmdictVersion,
+		Generation:    release.Generation,
+		DictDate:      stats.DictDate,
+		Words:         stats.Words,
+		Kanji:         stats.Kanji,
+		Radicals:      stats.Radicals,
+		Names:         stats.Names,
+		Sentences:     stats.Sentences,
+		Tags:          stats.Tags,
+		ImportedAt:    time.Now().UTC(),
+		Duration:      time.Since(startTime).Seconds(),
+	})
+}
+
+func pruneReleases(config *types.ServerConfig) error {
+	stale, err := database.StaleReleases(config.DataDir, config.JmdictVersion)
+	if err != nil {
+		return err
+	}
+
+	for _, release := range stale {
+		if err := database.DeleteRelease(config, release); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed old release %v\n", release.Generation)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/jmdict"
+	"github.com/izquiratops/tango/common/tatoeba"
+	"github.com/izquiratops/tango/common/utils"
+)
+
+// WordForms maps every kanji and kana form in JMdict to the IDs of the words written that way,
+// it's how Tatoeba headwords are linked to words
+type WordForms map[string][]string
+
+// LoadWordForms reads the JMdict release once more, keeping only the forms of every word
+func LoadWordForms(sourcePath string) (WordForms, error) {
+	source, err := openSource(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	decoder, err := jmdict.NewJMdictDecoder(source)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding JSON: %v", err)
+	}
+
+	forms := make(WordForms)
+	for {
+		word, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JSON: %v", err)
+		}
+
+		forms.add(&word)
+	}
+
+	return forms, nil
+}
+
+func (f WordForms) add(word *jmdict.JMdictWord) {
+	for _, kanji := range word.Kanji {
+		f.addForm(kanji.Text, word.ID)
+	}
+	for _, kana := range word.Kana {
+		f.addForm(kana.Text, word.ID)
+	}
+}
+
+func (f WordForms) addForm(form string, id string) {
+	if !utils.ContainsString(f[form], id) {
+		f[form] = append(f[form], id)
+	}
+}
+
+// Resolve returns the ID of the word a headword refers to, or "" when JMdict doesn't have it.
+// Homographs are told apart by the reading when the index gives one, otherwise the first word wins.
+func (f WordForms) Resolve(headword tatoeba.Headword) string {
+	ids := f[headword.Word]
+	if len(ids) == 0 {
+		return ""
+	}
+
+	if headword.Reading != "" {
+		for _, id := range ids {
+			if utils.ContainsString(f[headword.Reading], id) {
+				return id
+			}
+		}
+	}
+
+	return ids[0]
+}
+
+// ImportSentences reads the Tatoeba sentence pairs, links them to words with the Japanese indices
+// (when given) and writes them into the database
+func ImportSentences(db *database.Database, pairsPath string, indicesPath string, forms WordForms, stats *ImportStats) error {
+	sentences, order, err := loadSentencePairs(pairsPath)
+	if err != nil {
+		return err
+	}
+
+	if indicesPath != "" {
+		linked, err := linkSentences(indicesPath, sentences, forms)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Linked %d of %d sentences to words\n", linked, len(sentences))
+	}
+
+	ctx := context.Background()
+	storeBatch := make([]database.Sentence, 0, batchSize)
+	bleveBatch := db.SentencesIndex.NewBatch()
+
+	flush := func() error {
+		if err := db.Store.PutSentences(ctx, storeBatch); err != nil {
+			return fmt.Errorf("error writing sentences to store: %v", err)
+		}
+		if err := db.SentencesIndex.Batch(bleveBatch); err != nil {
+			return fmt.Errorf("error writing sentences to Bleve: %v", err)
+		}
+
+		storeBatch = storeBatch[:0]
+		bleveBatch = db.SentencesIndex.NewBatch()
+		return nil
+	}
+
+	for _, id := range order {
+		sentence := sentences[id]
+		storeBatch = append(storeBatch, *sentence)
+
+		if err := bleveBatch.Index(sentence.ID, ToSentenceSearchable(sentence)); err != nil {
+			return fmt.Errorf("error indexing sentence in Bleve: %v", err)
+		}
+
+		stats.Sentences++
+		stats.SentenceSample = addSample(stats.SentenceSample, stats.Sentences, sentence.ID)
+
+		if len(storeBatch) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(storeBatch) > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Sentences import completed. Processed %d sentences\n", stats.Sentences)
+	return nil
+}
+
+func ToSentenceSearchable(s *database.Sentence) database.SentenceSearchable {
+	return database.SentenceSearchable{
+		ID:       s.ID,
+		Japanese: s.Japanese,
+		English:  s.English,
+		Words:    s.WordIDs,
+	}
+}
+
+// loadSentencePairs groups the pairs by Japanese sentence, as every translation comes in its own line.
+// The IDs are returned in the order they were first read.
+func loadSentencePairs(path string) (map[string]*database.Sentence, []string, error) {
+	source, err := openSource(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer source.Close()
+
+	sentences := make(map[string]*database.Sentence)
+	var order []string
+
+	reader := tatoeba.NewPairReader(source)
+	for {
+		pair, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading sentence pairs: %v", err)
+		}
+
+		sentence, ok := sentences[pair.JapaneseID]
+		if !ok {
+			sentence = &database.Sentence{
+				ID:       pair.JapaneseID,
+				Japanese: pair.Japanese,
+				English:  make([]string, 0, 1),
+			}
+			sentences[pair.JapaneseID] = sentence
+			order = append(order, pair.JapaneseID)
+		}
+
+		if !utils.ContainsString(sentence.English, pair.English) {
+			sentence.English = append(sentence.English, pair.English)
+		}
+	}
+
+	return sentences, order, nil
+}
+
+// linkSentences adds the words of every indexed sentence, returning how many sentences got some word
+func linkSentences(path string, sentences map[string]*database.Sentence, forms WordForms) (int, error) {
+	source, err := openSource(path)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	linked := 0
+	reader := tatoeba.NewIndexReader(source)
+	for {
+		index, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error reading sentence indices: %v", err)
+		}
+
+		// Indices may refer to sentences without an English translation in the pairs file
+		sentence, ok := sentences[index.SentenceID]
+		if !ok {
+			continue
+		}
+
+		hadWords := len(sentence.WordIDs) > 0
+		for _, headword := range index.Headwords {
+			if id := forms.Resolve(headword); id != "" && !utils.ContainsString(sentence.WordIDs, id) {
+				sentence.WordIDs = append(sentence.WordIDs, id)
+			}
+		}
+
+		if !hadWords && len(sentence.WordIDs) > 0 {
+			linked++
+		}
+	}
+
+	return linked, nil
+}
@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/izquiratops/tango/common/jmdict"
+	"github.com/izquiratops/tango/common/tatoeba"
+)
+
+func testWordForms() WordForms {
+	forms := make(WordForms)
+	forms.add(&jmdict.JMdictWord{ID: "1", Kanji: []jmdict.JMdictKanji{{Text: "今日"}}, Kana: []jmdict.JMdictKana{{Text: "きょう"}}})
+	forms.add(&jmdict.JMdictWord{ID: "2", Kanji: []jmdict.JMdictKanji{{Text: "今日"}}, Kana: []jmdict.JMdictKana{{Text: "こんにち"}}})
+	forms.add(&jmdict.JMdictWord{ID: "3", Kana: []jmdict.JMdictKana{{Text: "パン"}}})
+	forms.add(&jmdict.JMdictWord{ID: "4", Kanji: []jmdict.JMdictKanji{{Text: "食べる"}, {Text: "喰べる"}}, Kana: []jmdict.JMdictKana{{Text: "たべる"}}})
+
+	return forms
+}
+
+func TestWordFormsResolve(t *testing.T) {
+	forms := testWordForms()
+
+	testCases := []struct {
+		headword tatoeba.Headword
+		expected string
+	}{
+		{tatoeba.Headword{Word: "今日"}, "1"},
+		{tatoeba.Headword{Word: "今日", Reading: "こんにち"}, "2"},
+		{tatoeba.Headword{Word: "今日", Reading: "いま"}, "1"},
+		{tatoeba.Headword{Word: "喰べる", Form: "喰べた"}, "4"},
+		{tatoeba.Headword{Word: "を"}, ""},
+	}
+
+	for _, tc := range testCases {
+		if got := forms.Resolve(tc.headword); got != tc.expected {
+			t.Errorf("Resolve(%+v) = %q, want %q", tc.headword, got, tc.expected)
+		}
+	}
+}
+
+func TestLoadAndLinkSentences(t *testing.T) {
+	dir := t.TempDir()
+	pairsPath := filepath.Join(dir, "jpn-eng.tsv")
+	indicesPath := filepath.Join(dir, "jpn_indices.csv")
+
+	pairs := "10\tパンを食べる。\t100\tI eat bread.\n" +
+		"10\tパンを食べる。\t101\tI'm eating bread.\n" +
+		"20\t今日は暑い。\t200\tIt's hot today.\n"
+	indices := "10\t100\tパン~ を 食べる\n" +
+		"10\t101\tパン 食べる\n" +
+		"30\t300\t今日\n"
+
+	if err := os.WriteFile(pairsPath, []byte(pairs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(indicesPath, []byte(indices), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sentences, order, err := loadSentencePairs(pairsPath)
+	if err != nil {
+		t.Fatalf("loadSentencePairs() error = %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"10", "20"}) {
+		t.Errorf("loadSentencePairs() order = %v", order)
+	}
+	if english := sentences["10"].English; !reflect.DeepEqual(english, []string{"I eat bread.", "I'm eating bread."}) {
+		t.Errorf("loadSentencePairs() translations = %v", english)
+	}
+
+	linked, err := linkSentences(indicesPath, sentences, testWordForms())
+	if err != nil {
+		t.Fatalf("linkSentences() error = %v", err)
+	}
+	if linked != 1 {
+		t.Errorf("linkSentences() linked %d sentences, want 1", linked)
+	}
+	if words := sentences["10"].WordIDs; !reflect.DeepEqual(words, []string{"3", "4"}) {
+		t.Errorf("linkSentences() words = %v, want [3 4]", words)
+	}
+	if words := sentences["20"].WordIDs; words != nil {
+		t.Errorf("Sentence without index has words %v", words)
+	}
+}
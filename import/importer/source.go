@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// Extensions accepted as dictionary sources, in order of preference
+	sourceExtensions = []string{".json", ".zip", ".tgz", ".tar.gz"}
+	// Tatoeba exports are tab separated, they're read as they come once extracted
+	tatoebaExtensions = []string{".tsv", ".csv"}
+)
+
+// findSource looks for a release of the given dictionary in dir, either extracted or still compressed
+// as downloaded from jmdict-simplified (e.g. jmdict-eng-3.6.1+20250101.json.zip)
+func findSource(dir string, prefix string) (string, error) {
+	return findFile(dir, prefix, sourceExtensions)
+}
+
+// findFile returns the last file in dir named after prefix, trying the extensions in order
+func findFile(dir string, prefix string, extensions []string) (string, error) {
+	for _, extension := range extensions {
+		matches, err := filepath.Glob(filepath.Join(dir, prefix+"*"+extension))
+		if err != nil {
+			return "", err
+		}
+
+		if len(matches) > 0 {
+			// Newest release last, as their names end with the release date
+			return matches[len(matches)-1], nil
+		}
+	}
+
+	return "", fmt.Errorf("no %v*{%v} file found in %v", prefix, strings.Join(extensions, ","), dir)
+}
+
+// openSource returns a reader for the JSON inside a .json, .zip or .tgz file.
+// Archives are decompressed while reading, nothing is extracted to disk.
+func openSource(path string) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(path, ".zip"):
+		return openZipSource(path)
+	case strings.HasSuffix(path, ".tgz"), strings.HasSuffix(path, ".tar.gz"):
+		return openTarSource(path)
+	default:
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening file: %v", err)
+		}
+		return file, nil
+	}
+}
+
+type zipSource struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+func (z *zipSource) Close() error {
+	return errors.Join(z.ReadCloser.Close(), z.archive.Close())
+}
+
+func openZipSource(path string) (io.ReadCloser, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening zip: %v", err)
+	}
+
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+
+		entry, err := file.Open()
+		if err != nil {
+			archive.Close()
+			return nil, fmt.Errorf("error opening %v in zip: %v", file.Name, err)
+		}
+
+		return &zipSource{ReadCloser: entry, archive: archive}, nil
+	}
+
+	archive.Close()
+	return nil, fmt.Errorf("no JSON file found in %v", path)
+}
+
+type tarSource struct {
+	io.Reader
+	gzipReader *gzip.Reader
+	file       *os.File
+}
+
+func (t *tarSource) Close() error {
+	return errors.Join(t.gzipReader.Close(), t.file.Close())
+}
+
+func openTarSource(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %v", err)
+	}
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error opening gzip: %v", err)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			gzipReader.Close()
+			file.Close()
+			return nil, fmt.Errorf("error reading tar: %v", err)
+		}
+
+		if header.Typeflag == tar.TypeReg && strings.HasSuffix(header.Name, ".json") {
+			return &tarSource{Reader: tarReader, gzipReader: gzipReader, file: file}, nil
+		}
+	}
+
+	gzipReader.Close()
+	file.Close()
+	return nil, fmt.Errorf("no JSON file found in %v", path)
+}
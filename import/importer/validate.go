@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+)
+
+// validateImport checks a staging release before it's promoted: the counts must match
+// what was read from the source, and a sample of words must be found both ways
+func validateImport(db *database.Database, stats *ImportStats) error {
+	ctx := context.Background()
+
+	if stats.Words == 0 {
+		return fmt.Errorf("no words were imported")
+	}
+
+	docCount, err := db.BleveIndex.DocCount()
+	if err != nil {
+		return fmt.Errorf("error counting Bleve documents: %v", err)
+	}
+	if docCount != uint64(stats.Words) {
+		return fmt.Errorf("bleve has %d documents, expected %d", docCount, stats.Words)
+	}
+
+	tags, err := db.Store.GetTags(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading tags: %v", err)
+	}
+	if len(tags) != stats.Tags {
+		return fmt.Errorf("store has %d tags, expected %d", len(tags), stats.Tags)
+	}
+
+	words, err := db.Store.GetWords(ctx, stats.SampleIDs)
+	if err != nil {
+		return fmt.Errorf("error reading sample words: %v", err)
+	}
+	if len(words) != len(stats.SampleIDs) {
+		return fmt.Errorf("store returned %d of %d sample words", len(words), len(stats.SampleIDs))
+	}
+
+	// Every sample word must be found by searching its own main form
+	for _, word := range words {
+		if word.MainWord.Word == "" {
+			continue
+		}
+
+		field := "kanji_exact"
+		if word.MainWord.Reading == "" {
+			field = "kana_exact"
+		}
+
+		formQuery := bleve.NewTermQuery(word.MainWord.Word)
+		formQuery.SetField(field)
+
+		searchRequest := bleve.NewSearchRequest(bleve.NewConjunctionQuery(bleve.NewDocIDQuery([]string{word.ID}), formQuery))
+		searchResults, err := db.BleveIndex.Search(searchRequest)
+		if err != nil {
+			return fmt.Errorf("error searching sample word %v: %v", word.ID, err)
+		}
+		if searchResults.Total != 1 {
+			return fmt.Errorf("sample word %v (%v) can't be found in Bleve", word.ID, word.MainWord.Word)
+		}
+	}
+
+	if err := validateKanji(db, stats); err != nil {
+		return err
+	}
+
+	if err := validateNames(db, stats); err != nil {
+		return err
+	}
+
+	if err := validateSentences(db, stats); err != nil {
+		return err
+	}
+
+	sampleLookups := len(words) + len(stats.KanjiSample) + len(stats.NameSample) + len(stats.SentenceSample)
+	fmt.Printf("Validated %d words, %d kanji, %d names, %d sentences, %d tags and %d sample lookups\n", stats.Words, stats.Kanji, stats.Names, stats.Sentences, stats.Tags, sampleLookups)
+	return nil
+}
+
+func validateKanji(db *database.Database, stats *ImportStats) error {
+	docCount, err := db.KanjiIndex.DocCount()
+	if err != nil {
+		return fmt.Errorf("error counting Bleve kanji: %v", err)
+	}
+	if docCount != uint64(stats.Kanji) {
+		return fmt.Errorf("bleve has %d kanji, expected %d", docCount, stats.Kanji)
+	}
+
+	radicals, err := db.Store.GetRadicals(context.Background())
+	if err != nil {
+		return fmt.Errorf("error reading radicals: %v", err)
+	}
+	if len(radicals) != stats.Radicals {
+		return fmt.Errorf("store has %d radicals, expected %d", len(radicals), stats.Radicals)
+	}
+
+	kanji, err := db.Store.GetKanji(context.Background(), stats.KanjiSample)
+	if err != nil {
+		return fmt.Errorf("error reading sample kanji: %v", err)
+	}
+	if len(kanji) != len(stats.KanjiSample) {
+		return fmt.Errorf("store returned %d of %d sample kanji", len(kanji), len(stats.KanjiSample))
+	}
+
+	return nil
+}
+
+func validateNames(db *database.Database, stats *ImportStats) error {
+	docCount, err := db.NamesIndex.DocCount()
+	if err != nil {
+		return fmt.Errorf("error counting Bleve names: %v", err)
+	}
+	if docCount != uint64(stats.Names) {
+		return fmt.Errorf("bleve has %d names, expected %d", docCount, stats.Names)
+	}
+
+	names, err := db.Store.GetNames(context.Background(), stats.NameSample)
+	if err != nil {
+		return fmt.Errorf("error reading sample names: %v", err)
+	}
+	if len(names) != len(stats.NameSample) {
+		return fmt.Errorf("store returned %d of %d sample names", len(names), len(stats.NameSample))
+	}
+
+	return nil
+}
+
+func validateSentences(db *database.Database, stats *ImportStats) error {
+	docCount, err := db.SentencesIndex.DocCount()
+	if err != nil {
+		return fmt.Errorf("error counting Bleve sentences: %v", err)
+	}
+	if docCount != uint64(stats.Sentences) {
+		return fmt.Errorf("bleve has %d sentences, expected %d", docCount, stats.Sentences)
+	}
+
+	sentences, err := db.Store.GetSentences(context.Background(), stats.SentenceSample)
+	if err != nil {
+		return fmt.Errorf("error reading sample sentences: %v", err)
+	}
+	if len(sentences) != len(stats.SentenceSample) {
+		return fmt.Errorf("store returned %d of %d sample sentences", len(sentences), len(stats.SentenceSample))
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/izquiratops/tango/common/database"
+	"github.com/izquiratops/tango/common/types"
+)
+
+// Verify checks a release that was already promoted the same way an import is checked before
+// promoting it: the counts of its manifest must match the indexes and the store, and a sample
+// of documents must be found both ways
+func Verify(config *types.ServerConfig, release database.Release) error {
+	manifest, err := release.ReadManifest()
+	if err != nil {
+		return err
+	}
+
+	db, err := database.OpenDatabase(context.Background(), config, release)
+	if err != nil {
+		return err
+	}
+	defer db.Close(context.Background())
+
+	stats := &ImportStats{
+		DictDate:  manifest.DictDate,
+		Words:     manifest.Words,
+		Kanji:     manifest.Kanji,
+		Radicals:  manifest.Radicals,
+		Names:     manifest.Names,
+		Sentences: manifest.Sentences,
+		Tags:      manifest.Tags,
+	}
+
+	samples := []struct {
+		index bleve.Index
+		ids   *[]string
+	}{
+		{db.BleveIndex, &stats.SampleIDs},
+		{db.KanjiIndex, &stats.KanjiSample},
+		{db.NamesIndex, &stats.NameSample},
+		{db.SentencesIndex, &stats.SentenceSample},
+	}
+	for _, sample := range samples {
+		if *sample.ids, err = sampleIndex(sample.index, sampleSize); err != nil {
+			return err
+		}
+	}
+
+	return validateImport(db, stats)
+}
+
+// sampleIndex returns the IDs of a random run of documents of the index
+func sampleIndex(index bleve.Index, size int) ([]string, error) {
+	count, err := index.DocCount()
+	if err != nil {
+		return nil, fmt.Errorf("error counting Bleve documents: %v", err)
+	}
+
+	from := 0
+	if int(count) > size {
+		from = rand.Intn(int(count) - size)
+	}
+
+	searchRequest := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), size, from, false)
+	searchResults, err := index.Search(searchRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error sampling Bleve documents: %v", err)
+	}
+
+	ids := make([]string, 0, len(searchResults.Hits))
+	for _, hit := range searchResults.Hits {
+		ids = append(ids, hit.ID)
+	}
+
+	return ids, nil
+}
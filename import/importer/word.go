@@ -1,4 +1,4 @@
-package main
+package importer
 
 import (
 	"strings"
@@ -78,5 +78,73 @@ func processSenseWord(entry *database.Word, word *jmdict.JMdictWord) {
 		}
 
 		entry.Meanings = append(entry.Meanings, strings.Join(glossList, "; "))
+		entry.Senses = append(entry.Senses, toSense(&sense, glossList))
 	}
 }
+
+func toSense(sense *jmdict.JMdictSense, glossList []string) database.Sense {
+	dbSense := database.Sense{
+		PartOfSpeech:   sense.PartOfSpeech,
+		AppliesToKanji: restrictionList(sense.AppliesToKanji),
+		AppliesToKana:  restrictionList(sense.AppliesToKana),
+		Field:          sense.Field,
+		Dialect:        sense.Dialect,
+		Misc:           sense.Misc,
+		Info:           sense.Info,
+		Related:        toCrossReferences(sense.Related),
+		Antonym:        toCrossReferences(sense.Antonym),
+		Glosses:        glossList,
+	}
+
+	for _, source := range sense.LanguageSource {
+		languageSource := database.LanguageSource{
+			Lang:  string(source.Lang),
+			Full:  source.Full,
+			Wasei: source.Wasei,
+		}
+		if source.Text != nil {
+			languageSource.Text = *source.Text
+		}
+
+		dbSense.LanguageSource = append(dbSense.LanguageSource, languageSource)
+	}
+
+	return dbSense
+}
+
+// restrictionList drops the "*" wildcard, an empty list already means the sense applies to everything
+func restrictionList(applies []string) []string {
+	if len(applies) == 0 || utils.ContainsString(applies, "*") {
+		return nil
+	}
+
+	return applies
+}
+
+func toCrossReferences(xrefs []jmdict.Xref) []database.CrossReference {
+	var references []database.CrossReference
+
+	for _, xref := range xrefs {
+		var reference database.CrossReference
+
+		switch {
+		case xref.Kanji != nil:
+			reference.Word = *xref.Kanji
+			if xref.Kana != nil {
+				reference.Reading = *xref.Kana
+			}
+		case xref.KanjiOrKana != nil:
+			reference.Word = *xref.KanjiOrKana
+		default:
+			continue
+		}
+
+		if xref.SenseIndex != nil {
+			reference.SenseIndex = *xref.SenseIndex
+		}
+
+		references = append(references, reference)
+	}
+
+	return references
+}
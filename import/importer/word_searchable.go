@@ -1,10 +1,12 @@
-package main
+package importer
 
 import (
 	"fmt"
 
 	"github.com/izquiratops/tango/common/database"
 	"github.com/izquiratops/tango/common/jmdict"
+	"github.com/izquiratops/tango/common/kana"
+	"github.com/izquiratops/tango/common/utils"
 )
 
 func ToWordSearchable(d *jmdict.JMdictWord) (database.WordSearchable, error) {
@@ -15,6 +17,7 @@ func ToWordSearchable(d *jmdict.JMdictWord) (database.WordSearchable, error) {
 		KanaExact:  make([]string, 0),
 		KanaChar:   make([]string, 0),
 		Meanings:   make([]string, 0),
+		Romaji:     make([]string, 0),
 	}
 
 	for _, k := range d.Kanji {
@@ -24,6 +27,7 @@ func ToWordSearchable(d *jmdict.JMdictWord) (database.WordSearchable, error) {
 
 		entry.KanjiExact = append(entry.KanjiExact, k.Text)
 		entry.KanjiChar = append(entry.KanjiChar, k.Text)
+		entry.Common = entry.Common || k.Common
 	}
 
 	for _, k := range d.Kana {
@@ -33,6 +37,11 @@ func ToWordSearchable(d *jmdict.JMdictWord) (database.WordSearchable, error) {
 
 		entry.KanaExact = append(entry.KanaExact, k.Text)
 		entry.KanaChar = append(entry.KanaChar, k.Text)
+		entry.Common = entry.Common || k.Common
+
+		if romaji := kana.ToRomaji(k.Text); !utils.ContainsString(entry.Romaji, romaji) {
+			entry.Romaji = append(entry.Romaji, romaji)
+		}
 	}
 
 	for _, s := range d.Sense {
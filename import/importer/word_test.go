@@ -1,4 +1,4 @@
-package main
+package importer
 
 import (
 	"reflect"
@@ -6,6 +6,7 @@ import (
 
 	"github.com/izquiratops/tango/common/database"
 	"github.com/izquiratops/tango/common/jmdict"
+	"github.com/izquiratops/tango/common/utils"
 )
 
 func TestToWord(t *testing.T) {
@@ -64,6 +65,7 @@ func TestToWord(t *testing.T) {
 				},
 			},
 			expected: database.Word{
+				ID:       "1586420",
 				MainWord: database.Furigana{Word: "暖かい", Reading: "あたたかい"},
 				OtherForms: []database.Furigana{
 					{Word: "温かい", Reading: "あたたかい"},
@@ -72,9 +74,73 @@ func TestToWord(t *testing.T) {
 				},
 				Common: true,
 				Meanings: []string{
-					"warm, mild, (pleasantly) hot",
-					"considerate, kind, genial",
-					"warm (of a colour), mellow",
+					"warm; mild; (pleasantly) hot",
+					"considerate; kind; genial",
+					"warm (of a colour); mellow",
+				},
+				Senses: []database.Sense{
+					{
+						PartOfSpeech: []string{"adj-i"},
+						Info:         []string{"暖かい usu. refers to air temperature"},
+						Glosses:      []string{"warm", "mild", "(pleasantly) hot"},
+					},
+					{
+						PartOfSpeech:   []string{"adj-i"},
+						Info:           []string{},
+						AppliesToKanji: []string{"温かい"},
+						Glosses:        []string{"considerate", "kind", "genial"},
+					},
+					{
+						PartOfSpeech:   []string{"adj-i"},
+						Info:           []string{},
+						AppliesToKanji: []string{"暖かい"},
+						Glosses:        []string{"warm (of a colour)", "mellow"},
+					},
+				},
+			},
+		},
+		{
+			name: "Test: パン",
+			input: jmdict.JMdictWord{
+				ID: "1101610",
+				Kana: []jmdict.JMdictKana{
+					{Text: "パン", Common: true, AppliesToKanji: []string{"*"}},
+				},
+				Sense: []jmdict.JMdictSense{
+					{
+						PartOfSpeech:   []string{"n"},
+						AppliesToKanji: []string{"*"},
+						AppliesToKana:  []string{"*"},
+						Related: []jmdict.Xref{
+							{Kanji: utils.ToStringPtr("食パン"), Kana: utils.ToStringPtr("しょくパン")},
+							{KanjiOrKana: utils.ToStringPtr("ブレッド"), SenseIndex: utils.ToIntPtr(1.0)},
+						},
+						LanguageSource: []jmdict.JMdictLanguageSource{
+							{Lang: "por", Full: true, Text: utils.ToStringPtr("pão")},
+						},
+						Gloss: []jmdict.JMdictGloss{
+							{Lang: "eng", Text: "bread"},
+						},
+					},
+				},
+			},
+			expected: database.Word{
+				ID:       "1101610",
+				MainWord: database.Furigana{Word: "パン"},
+				Common:   true,
+				Meanings: []string{"bread"},
+				Senses: []database.Sense{
+					{
+						PartOfSpeech: []string{"n"},
+						Related: []database.CrossReference{
+							{Word: "食パン", Reading: "しょくパン"},
+							{Word: "ブレッド", SenseIndex: 1},
+						},
+						LanguageSource: []database.LanguageSource{
+							{Lang: "por", Text: "pão", Full: true},
+						},
+						Glosses: []string{"bread"},
+					},
 				},
 			},
 		},
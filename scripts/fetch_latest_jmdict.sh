@@ -1,40 +1,31 @@
 #!/bin/bash
 
-# Detect OS
-if [[ "$OSTYPE" == "linux-gnu"* ]] || [[ "$OSTYPE" == "darwin"* ]]; then
-    OS="UNIX"
-elif [[ "$OSTYPE" == "msys" ]] || [[ "$OSTYPE" == "cygwin" ]] || [[ "$OSTYPE" == "win32" ]]; then
-    OS="Windows"
-else
-    echo "Unsupported operating system"
-    exit 1
-fi
+# The importer reads the release zips directly, so there's nothing to extract
 
 JSON_RESPONSE=$(curl -s https://api.github.com/repos/scriptin/jmdict-simplified/releases/latest)
 
-LATEST_RELEASE=$(echo "$JSON_RESPONSE" | jq '.assets[] | select(.name | test("jmdict-eng-[0-9].*\\.zip"))')
+download_asset() {
+    local PATTERN=$1
+    local LATEST_RELEASE=$(echo "$JSON_RESPONSE" | jq --arg pattern "$PATTERN" '.assets[] | select(.name | test($pattern))')
 
-if [ -z "$LATEST_RELEASE" ]; then
-    echo "No matching item found."
-else
-    echo "Matching item name: $LATEST_RELEASE"
-    BROWSER_DOWNLOAD_URL=$(echo "$LATEST_RELEASE" | jq -r '.browser_download_url')
+    if [ -z "$LATEST_RELEASE" ]; then
+        echo "No matching item found for $PATTERN."
+    else
+        echo "Matching item name: $LATEST_RELEASE"
+        BROWSER_DOWNLOAD_URL=$(echo "$LATEST_RELEASE" | jq -r '.browser_download_url')
+        RELEASE_NAME=$(echo "$LATEST_RELEASE" | jq -r '.name')
 
-    # Follow HTTP redirections (301) https://askubuntu.com/a/1036492
-    TMP_FILE="jmdict.zip"
-    curl -sL -H 'Accept-encoding: gzip' "$BROWSER_DOWNLOAD_URL" -o "$TMP_FILE"
+        # Follow HTTP redirections (301) https://askubuntu.com/a/1036492
+        curl -sL "$BROWSER_DOWNLOAD_URL" -o "$RELEASE_NAME"
 
-    if [ "$OS" = "UNIX" ]; then
-        echo "Extracting with unzip..."
-        unzip -o "$TMP_FILE"
-    else
-        # Trying to unzip it with PowerShell using tar -xf doesn't work and I don't know why!
-        echo "Extracting with PowerShell..."
-        powershell -command "Expand-Archive -Path "$TMP_FILE" -DestinationPath . -Force"
+        echo "Downloaded $RELEASE_NAME"
     fi
+}
 
-    # Zip is not needed anymore 💫
-    rm jmdict.zip
-fi
+download_asset "jmdict-eng-[0-9].*\\.zip"
+download_asset "kanjidic2-en-[0-9].*\\.zip"
+download_asset "jmnedict-all-[0-9].*\\.zip"
+download_asset "kradfile-[0-9].*\\.zip"
+download_asset "radkfile-[0-9].*\\.zip"
 
-read -p "Press any key to continue" x
\ No newline at end of file
+read -p "Press any key to continue" x